@@ -82,6 +82,14 @@ type BookkeeperSpec struct {
 	// in bookkeeper. Some examples can be found here
 	// https://github.com/apache/bookkeeper/blob/master/docker/README.md
 	Options map[string]string `json:"options"`
+
+	// Tolerations specifies the tolerations to apply on bookie pods,
+	// allowing them to be scheduled onto nodes with matching taints
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity specifies the scheduling constraints for bookie pods.
+	// If set, it replaces the default pod anti-affinity applied by the operator
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
 }
 
 func (s *BookkeeperSpec) withDefaults() (changed bool) {
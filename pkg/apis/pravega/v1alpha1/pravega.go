@@ -11,6 +11,9 @@
 package v1alpha1
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/pravega/pravega-operator/pkg/controller/config"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -63,6 +66,10 @@ const (
 
 	// DefaultSegmentStoreLimitMemory is the default memory limit for Pravega
 	DefaultSegmentStoreLimitMemory = "2Gi"
+
+	// DefaultCanarySoakTimeSeconds is the default soak period of the canary
+	// segment store pod during upgrades
+	DefaultCanarySoakTimeSeconds = 600
 )
 
 // PravegaSpec defines the configuration of Pravega
@@ -83,6 +90,16 @@ type PravegaSpec struct {
 	// By default, "pravega/pravega" will be used.
 	Image *PravegaImageSpec `json:"image"`
 
+	// ControllerImage overrides Image for the controller component, for
+	// deployments that mix differently built images per component.
+	// The image tag is still derived from spec.Version
+	ControllerImage *PravegaImageSpec `json:"controllerImage,omitempty"`
+
+	// SegmentStoreImage overrides Image for the segment store component, for
+	// deployments that mix differently built images per component.
+	// The image tag is still derived from spec.Version
+	SegmentStoreImage *PravegaImageSpec `json:"segmentStoreImage,omitempty"`
+
 	// Options is the Pravega configuration that is passed to the Pravega processes
 	// as JAVA_OPTS. See the following file for a complete list of options:
 	// https://github.com/pravega/pravega/blob/master/config/config.properties
@@ -106,6 +123,26 @@ type PravegaSpec struct {
 	// If not specified, Kubernetes will automatically assign the default service account in the namespace
 	SegmentStoreServiceAccountName string `json:"segmentStoreServiceAccountName,omitempty"`
 
+	// ControllerServiceAccountAnnotations are added to the generated
+	// controller service account, e.g. "eks.amazonaws.com/role-arn" or
+	// "iam.gke.io/gcp-service-account" for cloud workload identity
+	ControllerServiceAccountAnnotations map[string]string `json:"controllerServiceAccountAnnotations,omitempty"`
+
+	// SegmentStoreServiceAccountAnnotations are added to the generated
+	// segment store service account. With workload identity set up here,
+	// tier-2 storage can run on the ambient credentials chain instead of
+	// static keys; Azure additionally needs the
+	// "azure.workload.identity/use" pod label via segmentStorePodLabels
+	SegmentStoreServiceAccountAnnotations map[string]string `json:"segmentStoreServiceAccountAnnotations,omitempty"`
+
+	// CreateServiceAccounts indicates whether the operator creates per-component
+	// service accounts, together with a role and role binding that allow pods
+	// to read the services of the cluster. When enabled, unset service account
+	// names default to "<cluster>-pravega-controller" and
+	// "<cluster>-pravega-segmentstore".
+	// Defaults to false
+	CreateServiceAccounts bool `json:"createServiceAccounts,omitempty"`
+
 	// ControllerResources specifies the request and limit of resources that controller can have.
 	// ControllerResources includes CPU and memory resources
 	ControllerResources *v1.ResourceRequirements `json:"controllerResources,omitempty"`
@@ -113,9 +150,559 @@ type PravegaSpec struct {
 	// SegmentStoreResources specifies the request and limit of resources that segmentStore can have.
 	// SegmentStoreResources includes CPU and memory resources
 	SegmentStoreResources *v1.ResourceRequirements `json:"segmentStoreResources,omitempty"`
+
+	// ControllerTolerations specifies the tolerations to apply on controller pods,
+	// allowing them to be scheduled onto nodes with matching taints
+	ControllerTolerations []v1.Toleration `json:"controllerTolerations,omitempty"`
+
+	// SegmentStoreTolerations specifies the tolerations to apply on segment store pods,
+	// allowing them to be scheduled onto nodes with matching taints
+	SegmentStoreTolerations []v1.Toleration `json:"segmentStoreTolerations,omitempty"`
+
+	// ControllerAffinity specifies the scheduling constraints for controller pods.
+	// If set, it replaces the default pod anti-affinity applied by the operator
+	ControllerAffinity *v1.Affinity `json:"controllerAffinity,omitempty"`
+
+	// SegmentStoreAffinity specifies the scheduling constraints for segment store pods.
+	// If set, it replaces the default pod anti-affinity applied by the operator
+	SegmentStoreAffinity *v1.Affinity `json:"segmentStoreAffinity,omitempty"`
+
+	// ControllerPodLabels defines additional labels to add to controller pods.
+	// Labels set by the operator take precedence
+	ControllerPodLabels map[string]string `json:"controllerPodLabels,omitempty"`
+
+	// ControllerPodAnnotations defines additional annotations to add to controller pods
+	ControllerPodAnnotations map[string]string `json:"controllerPodAnnotations,omitempty"`
+
+	// SegmentStorePodLabels defines additional labels to add to segment store pods.
+	// Labels set by the operator take precedence
+	SegmentStorePodLabels map[string]string `json:"segmentStorePodLabels,omitempty"`
+
+	// SegmentStorePodAnnotations defines additional annotations to add to segment store pods
+	SegmentStorePodAnnotations map[string]string `json:"segmentStorePodAnnotations,omitempty"`
+
+	// ControllerSeccompProfile attaches a seccomp profile to the controller
+	// pods, e.g. "runtime/default" or "localhost/<profile>". It is emitted
+	// as the corresponding pod annotation and survives reconciles
+	ControllerSeccompProfile string `json:"controllerSeccompProfile,omitempty"`
+
+	// SegmentStoreSeccompProfile attaches a seccomp profile to the segment
+	// store pods
+	SegmentStoreSeccompProfile string `json:"segmentStoreSeccompProfile,omitempty"`
+
+	// ControllerApparmorProfile attaches an AppArmor profile to the main
+	// controller container, e.g. "runtime/default" or
+	// "localhost/<profile>"
+	ControllerApparmorProfile string `json:"controllerApparmorProfile,omitempty"`
+
+	// SegmentStoreApparmorProfile attaches an AppArmor profile to the main
+	// segment store container
+	SegmentStoreApparmorProfile string `json:"segmentStoreApparmorProfile,omitempty"`
+
+	// ControllerServiceAnnotations defines annotations to add to the controller service
+	ControllerServiceAnnotations map[string]string `json:"controllerServiceAnnotations,omitempty"`
+
+	// SegmentStoreServiceAnnotations defines annotations to add to the segment
+	// store services, including the headless service
+	SegmentStoreServiceAnnotations map[string]string `json:"segmentStoreServiceAnnotations,omitempty"`
+
+	// ControllerSessionAffinity sets the session affinity of the controller
+	// service. Options are "ClientIP" and "None".
+	// By default, no session affinity is configured
+	ControllerSessionAffinity v1.ServiceAffinity `json:"controllerSessionAffinity,omitempty"`
+
+	// ControllerSessionAffinityTimeoutSeconds sets the sticky time of
+	// "ClientIP" session affinity.
+	// If not specified, the Kubernetes default of 3 hours is used
+	ControllerSessionAffinityTimeoutSeconds *int32 `json:"controllerSessionAffinityTimeoutSeconds,omitempty"`
+
+	// SplitControllerServices makes the operator create two controller
+	// services, one for the REST endpoint and one for the gRPC endpoint,
+	// instead of a single combined service. This allows the endpoints to be
+	// exposed through different load balancer flavors
+	SplitControllerServices bool `json:"splitControllerServices,omitempty"`
+
+	// ControllerRestService customizes the REST service when
+	// SplitControllerServices is enabled
+	ControllerRestService *ServiceOverrideSpec `json:"controllerRestService,omitempty"`
+
+	// ControllerGrpcService customizes the gRPC service when
+	// SplitControllerServices is enabled
+	ControllerGrpcService *ServiceOverrideSpec `json:"controllerGrpcService,omitempty"`
+
+	// CreateControllerHeadlessService makes the operator create a headless
+	// service for the controllers, so in-cluster gRPC clients can resolve
+	// the individual controller pods and balance requests client-side.
+	// Defaults to false
+	CreateControllerHeadlessService bool `json:"createControllerHeadlessService,omitempty"`
+
+	// ControllerRestPort is the port of the controller REST endpoint. The
+	// port is propagated to the container, the services, the config map and
+	// the generated Ingress/Routes.
+	// Defaults to 10080
+	ControllerRestPort int32 `json:"controllerRestPort,omitempty"`
+
+	// ControllerGrpcPort is the port of the controller gRPC endpoint. The
+	// port is propagated in the same way as ControllerRestPort.
+	// Defaults to 9090
+	ControllerGrpcPort int32 `json:"controllerGrpcPort,omitempty"`
+
+	// ControllerRestNodePort fixes the node port of the controller REST
+	// endpoint when external access uses node ports.
+	// By default, a random port is allocated
+	ControllerRestNodePort int32 `json:"controllerRestNodePort,omitempty"`
+
+	// ControllerGrpcNodePort fixes the node port of the controller gRPC
+	// endpoint when external access uses node ports.
+	// By default, a random port is allocated
+	ControllerGrpcNodePort int32 `json:"controllerGrpcNodePort,omitempty"`
+
+	// SegmentStoreExternalServiceNameTemplate customizes the name of the
+	// per-pod segment store external services. The tokens "{cluster}" and
+	// "{ordinal}" are replaced by the cluster name and the pod ordinal.
+	// By default, services are named "{cluster}-pravega-segmentstore-{ordinal}"
+	SegmentStoreExternalServiceNameTemplate string `json:"segmentStoreExternalServiceNameTemplate,omitempty"`
+
+	// SegmentStoreExternalServiceAnnotations defines annotations to add to
+	// the per-pod segment store external services. The tokens "{cluster}"
+	// and "{ordinal}" are replaced in the annotation values
+	SegmentStoreExternalServiceAnnotations map[string]string `json:"segmentStoreExternalServiceAnnotations,omitempty"`
+
+	// SegmentStoreExternalPort sets the port exposed by the per-pod segment
+	// store external services. The target port remains 12345.
+	// Defaults to 12345
+	SegmentStoreExternalPort int32 `json:"segmentStoreExternalPort,omitempty"`
+
+	// SegmentStoreHostNetwork runs segment store pods in the host network
+	// namespace to avoid overlay-network overhead for bulk segment traffic.
+	// Pods then advertise their node's IP address since the pod IP and node
+	// IP coincide. The DNS policy defaults to "ClusterFirstWithHostNet"
+	// unless SegmentStoreDNSPolicy is set.
+	// Defaults to false
+	SegmentStoreHostNetwork bool `json:"segmentStoreHostNetwork,omitempty"`
+
+	// SegmentStoreAdvertisedPort sets the port segment stores advertise to
+	// clients, for NAT environments where the reachable port differs from
+	// the container port.
+	// Defaults to SegmentStoreExternalPort when external access is enabled
+	SegmentStoreAdvertisedPort int32 `json:"segmentStoreAdvertisedPort,omitempty"`
+
+	// SegmentStoreNodePortBase fixes the node ports of the per-pod segment
+	// store services when external access uses node ports. Segment store pod
+	// N is assigned node port SegmentStoreNodePortBase + N.
+	// By default, random ports are allocated
+	SegmentStoreNodePortBase int32 `json:"segmentStoreNodePortBase,omitempty"`
+
+	// ControllerPriorityClassName configures the priority class used on controller pods.
+	// If not specified, pods are created with the default priority
+	ControllerPriorityClassName string `json:"controllerPriorityClassName,omitempty"`
+
+	// SegmentStorePriorityClassName configures the priority class used on segment store pods.
+	// If not specified, pods are created with the default priority
+	SegmentStorePriorityClassName string `json:"segmentStorePriorityClassName,omitempty"`
+
+	// ControllerPodSecurityContext specifies the pod-level security attributes
+	// applied to controller pods
+	ControllerPodSecurityContext *v1.PodSecurityContext `json:"controllerPodSecurityContext,omitempty"`
+
+	// ControllerSecurityContext specifies the security attributes applied to the
+	// controller container
+	ControllerSecurityContext *v1.SecurityContext `json:"controllerSecurityContext,omitempty"`
+
+	// SegmentStorePodSecurityContext specifies the pod-level security attributes
+	// applied to segment store pods
+	SegmentStorePodSecurityContext *v1.PodSecurityContext `json:"segmentStorePodSecurityContext,omitempty"`
+
+	// SegmentStoreSecurityContext specifies the security attributes applied to the
+	// segment store container
+	SegmentStoreSecurityContext *v1.SecurityContext `json:"segmentStoreSecurityContext,omitempty"`
+
+	// ControllerInitContainers defines the init containers to run before the
+	// controller container starts
+	ControllerInitContainers []v1.Container `json:"controllerInitContainers,omitempty"`
+
+	// SegmentStoreInitContainers defines the init containers to run before the
+	// segment store container starts
+	SegmentStoreInitContainers []v1.Container `json:"segmentStoreInitContainers,omitempty"`
+
+	// ControllerSidecars defines additional containers to run alongside the
+	// controller container in the same pod
+	ControllerSidecars []v1.Container `json:"controllerSidecars,omitempty"`
+
+	// SegmentStoreSidecars defines additional containers to run alongside the
+	// segment store container in the same pod
+	SegmentStoreSidecars []v1.Container `json:"segmentStoreSidecars,omitempty"`
+
+	// ControllerTerminationGracePeriodSeconds is the time given to controller
+	// pods to shut down gracefully. If not specified, the Kubernetes default
+	// of 30 seconds is used
+	ControllerTerminationGracePeriodSeconds *int64 `json:"controllerTerminationGracePeriodSeconds,omitempty"`
+
+	// SegmentStoreTerminationGracePeriodSeconds is the time given to segment
+	// store pods to shut down gracefully. If not specified, the Kubernetes
+	// default of 30 seconds is used
+	SegmentStoreTerminationGracePeriodSeconds *int64 `json:"segmentStoreTerminationGracePeriodSeconds,omitempty"`
+
+	// SegmentStoreDrainOnShutdown indicates whether a preStop hook is added to
+	// segment store containers that waits for established client connections
+	// to drain before the process receives SIGTERM. It is meant to be used
+	// together with an increased termination grace period.
+	// Defaults to false
+	SegmentStoreDrainOnShutdown bool `json:"segmentStoreDrainOnShutdown,omitempty"`
+
+	// ControllerDNSPolicy sets the DNS policy of controller pods.
+	// If not specified, the Kubernetes default "ClusterFirst" is used
+	ControllerDNSPolicy v1.DNSPolicy `json:"controllerDNSPolicy,omitempty"`
+
+	// ControllerDNSConfig specifies the DNS parameters of controller pods
+	ControllerDNSConfig *v1.PodDNSConfig `json:"controllerDNSConfig,omitempty"`
+
+	// SegmentStoreDNSPolicy sets the DNS policy of segment store pods.
+	// If not specified, the Kubernetes default "ClusterFirst" is used
+	SegmentStoreDNSPolicy v1.DNSPolicy `json:"segmentStoreDNSPolicy,omitempty"`
+
+	// SegmentStoreDNSConfig specifies the DNS parameters of segment store pods
+	SegmentStoreDNSConfig *v1.PodDNSConfig `json:"segmentStoreDNSConfig,omitempty"`
+
+	// HostAliases is a list of hosts and IPs injected into the /etc/hosts file
+	// of controller and segment store pods, for endpoints such as tier 2
+	// storage systems that are not resolvable through cluster DNS
+	HostAliases []v1.HostAlias `json:"hostAliases,omitempty"`
+
+	// ControllerRuntimeClassName refers to the RuntimeClass used to run
+	// controller pods, for clusters that mandate sandboxed runtimes
+	ControllerRuntimeClassName *string `json:"controllerRuntimeClassName,omitempty"`
+
+	// SegmentStoreRuntimeClassName refers to the RuntimeClass used to run
+	// segment store pods, for clusters that mandate sandboxed runtimes
+	SegmentStoreRuntimeClassName *string `json:"segmentStoreRuntimeClassName,omitempty"`
+
+	// ControllerReadinessProbe overrides the timings of the controller
+	// readiness probe
+	ControllerReadinessProbe *ProbeSpec `json:"controllerReadinessProbe,omitempty"`
+
+	// ControllerLivenessProbe overrides the timings of the controller
+	// liveness probe
+	ControllerLivenessProbe *ProbeSpec `json:"controllerLivenessProbe,omitempty"`
+
+	// SegmentStoreReadinessProbe overrides the timings of the segment store
+	// readiness probe
+	SegmentStoreReadinessProbe *ProbeSpec `json:"segmentStoreReadinessProbe,omitempty"`
+
+	// SegmentStoreLivenessProbe overrides the timings of the segment store
+	// liveness probe
+	SegmentStoreLivenessProbe *ProbeSpec `json:"segmentStoreLivenessProbe,omitempty"`
+
+	// ControllerVolumes defines additional volumes to add to controller pods
+	ControllerVolumes []v1.Volume `json:"controllerVolumes,omitempty"`
+
+	// ControllerVolumeMounts defines additional volume mounts to add to the
+	// controller container
+	ControllerVolumeMounts []v1.VolumeMount `json:"controllerVolumeMounts,omitempty"`
+
+	// SegmentStoreVolumes defines additional volumes to add to segment store pods
+	SegmentStoreVolumes []v1.Volume `json:"segmentStoreVolumes,omitempty"`
+
+	// SegmentStoreVolumeMounts defines additional volume mounts to add to the
+	// segment store container
+	SegmentStoreVolumeMounts []v1.VolumeMount `json:"segmentStoreVolumeMounts,omitempty"`
+
+	// ControllerPodTemplateOverride is a partial pod template that is
+	// strategically merged over the controller pod template generated by the
+	// operator. It is an escape hatch for pod fields the spec does not model
+	// and takes precedence over the generated values
+	ControllerPodTemplateOverride *v1.PodTemplateSpec `json:"controllerPodTemplateOverride,omitempty"`
+
+	// SegmentStorePodTemplateOverride is a partial pod template that is
+	// strategically merged over the segment store pod template generated by
+	// the operator. It is an escape hatch for pod fields the spec does not
+	// model and takes precedence over the generated values
+	SegmentStorePodTemplateOverride *v1.PodTemplateSpec `json:"segmentStorePodTemplateOverride,omitempty"`
+
+	// ReadOnlyRootFilesystem makes the controller and segment store containers
+	// run with a read-only root filesystem. The operator mounts emptyDir
+	// volumes over the scratch directories (/tmp and the Pravega log
+	// directory) so the processes can still start.
+	// Defaults to false
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// ControllerJvmOptions are JVM flags (e.g. "-Xmx2g") appended to the
+	// generated JAVA_OPTS of the controllers. Use Options for -D style
+	// Pravega configuration
+	ControllerJvmOptions []string `json:"controllerJvmOptions,omitempty"`
+
+	// SegmentStoreJvmOptions are JVM flags appended to the generated
+	// JAVA_OPTS of the segment stores
+	SegmentStoreJvmOptions []string `json:"segmentStoreJvmOptions,omitempty"`
+
+	// ControllerLogLevel is the log level of the controller pods, e.g.
+	// "INFO" or "DEBUG". A change rolls the pods through the config hash;
+	// Pravega offers no API to change the level of a running process.
+	// By default, the level baked into the image is used
+	ControllerLogLevel string `json:"controllerLogLevel,omitempty"`
+
+	// SegmentStoreLogLevel is the log level of the segment store pods.
+	// Takes precedence over DebugLogging
+	SegmentStoreLogLevel string `json:"segmentStoreLogLevel,omitempty"`
+
+	// ControllerLogbackConfigMap is the name of a config map holding a
+	// custom logback configuration under the "logback.xml" key. It is
+	// mounted on the controller pods and the JVM is pointed at it, so
+	// log formats and per-package levels can be changed without a custom
+	// image
+	ControllerLogbackConfigMap string `json:"controllerLogbackConfigMap,omitempty"`
+
+	// SegmentStoreLogbackConfigMap is the name of a config map holding a
+	// custom logback configuration for the segment store pods
+	SegmentStoreLogbackConfigMap string `json:"segmentStoreLogbackConfigMap,omitempty"`
+
+	// ControllerAuditLog routes the controller audit trail — stream
+	// lifecycle operations and auth decisions — to a dedicated appender,
+	// so SIEM pipelines can capture it without parsing the regular log.
+	// Cannot be combined with ControllerLogbackConfigMap, which replaces
+	// the generated logback configuration wholesale.
+	// By default, no audit appender is configured
+	ControllerAuditLog *AuditLogSpec `json:"controllerAuditLog,omitempty"`
+
+	// ControllerHeapPercent sizes the controller heap (-Xmx) as a
+	// percentage of the declared memory limit. Only applies when the
+	// controller resources declare a memory limit.
+	// By default, 50 percent of the limit
+	ControllerHeapPercent int32 `json:"controllerHeapPercent,omitempty"`
+
+	// ControllerDirectMemoryPercent sizes -XX:MaxDirectMemorySize of the
+	// controllers as a percentage of the declared memory limit.
+	// By default, 25 percent of the limit
+	ControllerDirectMemoryPercent int32 `json:"controllerDirectMemoryPercent,omitempty"`
+
+	// SegmentStoreHeapPercent sizes the segment store heap (-Xmx) as a
+	// percentage of the declared memory limit. Only applies when the
+	// segment store resources declare a memory limit.
+	// By default, 33 percent of the limit
+	SegmentStoreHeapPercent int32 `json:"segmentStoreHeapPercent,omitempty"`
+
+	// SegmentStoreDirectMemoryPercent sizes -XX:MaxDirectMemorySize of
+	// the segment stores as a percentage of the declared memory limit.
+	// The segment store cache lives in direct memory, so it gets the
+	// larger share.
+	// By default, 50 percent of the limit
+	SegmentStoreDirectMemoryPercent int32 `json:"segmentStoreDirectMemoryPercent,omitempty"`
+
+	// ControllerSecretName references a pre-existing secret with the
+	// sensitive controller configuration, such as TOKEN_SIGNING_KEY. The
+	// keys are injected into the controller environment.
+	// If empty, the operator generates a secret with default values
+	ControllerSecretName string `json:"controllerSecretName,omitempty"`
+
+	// ControllerOptions is the Pravega configuration applied only to
+	// controllers, merged over Options. Keys present in both maps take the
+	// value from ControllerOptions
+	ControllerOptions map[string]string `json:"controllerOptions,omitempty"`
+
+	// SegmentStoreOptions is the Pravega configuration applied only to
+	// segment stores, merged over Options
+	SegmentStoreOptions map[string]string `json:"segmentStoreOptions,omitempty"`
+
+	// AdminGateway enables Pravega's admin gateway listener on segment store
+	// pods and exposes it through an internal-only service, so pravega-cli
+	// and pravega-admin can be run against live clusters
+	AdminGateway *AdminGatewaySpec `json:"adminGateway,omitempty"`
+
+	// ServiceAccountTokens defines bound service-account token volumes that
+	// are mounted on controller and segment store pods, for workloads that
+	// authenticate against external systems with audience-scoped tokens
+	ServiceAccountTokens []ServiceAccountTokenSpec `json:"serviceAccountTokens,omitempty"`
+
+	// ExtraConfig mounts existing ConfigMaps or Secrets as config files on
+	// both controller and segment store pods
+	ExtraConfig []ExtraConfigSpec `json:"extraConfig,omitempty"`
+
+	// Metrics configures Pravega's metrics reporters on both controller
+	// and segment store pods.
+	// By default, metrics are not enabled
+	Metrics *MetricsSpec `json:"metrics,omitempty"`
+
+	// GcProfile selects the garbage collector of the controller and
+	// segment store JVMs. Options are "g1", "zgc" and "default". ZGC
+	// requires the JDK 11 images bundled from Pravega 0.5.0 onwards;
+	// on older versions the selection falls back to G1.
+	// By default, the collector baked into the image is used
+	GcProfile string `json:"gcProfile,omitempty"`
+
+	// SegmentContainerCount is the total number of segment containers the
+	// cluster shards segments into. It must be at least the segment store
+	// replica count and cannot be changed once the cluster has
+	// bootstrapped: the container count determines how data is mapped to
+	// tier-2 and changing it corrupts the cluster.
+	// By default, the Pravega built-in count is used
+	SegmentContainerCount int32 `json:"segmentContainerCount,omitempty"`
+
+	// SegmentStoreCanaryUpgrade makes upgrades move a single segment store
+	// pod to the new version first and soak it for a period before the
+	// remaining pods follow, reducing the blast radius of a bad release.
+	// By default, all segment store pods roll without a canary phase
+	SegmentStoreCanaryUpgrade *CanaryUpgradeSpec `json:"segmentStoreCanaryUpgrade,omitempty"`
+
+	// ControllerDebug enables JMX and/or JDWP on the controller pods. The
+	// ports are only exposed on an internal debug service, never on the
+	// external services
+	ControllerDebug *DebugSpec `json:"controllerDebug,omitempty"`
+
+	// SegmentStoreDebug enables JMX and/or JDWP on the segment store pods
+	SegmentStoreDebug *DebugSpec `json:"segmentStoreDebug,omitempty"`
+}
+
+// CanaryUpgradeSpec holds a single segment store pod on the new version
+// for a soak period before the rest of the stateful set rolls. The canary
+// is the highest-ordinal pod; the others are held back through the
+// rolling-update partition of the stateful set
+type CanaryUpgradeSpec struct {
+	// Enabled turns on the canary phase of segment store upgrades
+	Enabled bool `json:"enabled"`
+
+	// SoakTimeSeconds is how long the canary pod must stay healthy on the
+	// new version before the remaining pods are released.
+	// Defaults to 600 seconds
+	SoakTimeSeconds int32 `json:"soakTimeSeconds,omitempty"`
+}
+
+// IsEnabled returns whether the canary phase is configured
+func (c *CanaryUpgradeSpec) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// SoakTime returns the soak period of the canary pod
+func (c *CanaryUpgradeSpec) SoakTime() time.Duration {
+	if c == nil || c.SoakTimeSeconds == 0 {
+		return DefaultCanarySoakTimeSeconds * time.Second
+	}
+	return time.Duration(c.SoakTimeSeconds) * time.Second
+}
+
+// AdminGatewaySpec defines the admin gateway listener of the segment stores
+type AdminGatewaySpec struct {
+	// Port the admin gateway listens on.
+	// Defaults to 9999
+	Port int32 `json:"port,omitempty"`
+
+	// CredentialsSecret names a secret with the admin credentials, mounted
+	// on segment store pods when specified
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// ServiceOverrideSpec customizes one of the split controller services
+type ServiceOverrideSpec struct {
+	// Type of the service.
+	// If not specified, falls back to the external access type when external
+	// access is enabled, or "ClusterIP" otherwise
+	Type v1.ServiceType `json:"type,omitempty"`
+
+	// Annotations to be added to the service
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DebugSpec enables the JVM debug listeners of a component. JMX remote
+// access is configured without authentication or SSL, which is why the
+// ports only ever appear on the in-cluster debug service
+type DebugSpec struct {
+	// JmxPort enables a JMX listener on the given port
+	JmxPort int32 `json:"jmxPort,omitempty"`
+
+	// JdwpPort enables a JDWP debug agent on the given port. The JVM
+	// starts without waiting for a debugger to attach
+	JdwpPort int32 `json:"jdwpPort,omitempty"`
+}
+
+// MetricsSpec configures Pravega's metrics reporters
+type MetricsSpec struct {
+	// InfluxDB enables the InfluxDB reporter
+	InfluxDB *InfluxDBSpec `json:"influxdb,omitempty"`
+
+	// StatsD enables the StatsD reporter
+	StatsD *StatsDSpec `json:"statsd,omitempty"`
+
+	// Prometheus enables the Prometheus reporter, opens the metrics port
+	// on the containers and services and annotates the pods for scraping
+	Prometheus *PrometheusSpec `json:"prometheus,omitempty"`
+}
+
+// StatsDSpec defines the StatsD endpoint metrics are reported to
+type StatsDSpec struct {
+	// Host of the StatsD daemon
+	Host string `json:"host"`
+
+	// Port of the StatsD daemon.
+	// By default, port 8125 is used
+	Port int32 `json:"port,omitempty"`
+}
+
+// PrometheusSpec defines the Prometheus metrics endpoint of the pods
+type PrometheusSpec struct {
+	// Port the metrics endpoint listens on.
+	// By default, port 9091 is used
+	Port int32 `json:"port,omitempty"`
+}
+
+// InfluxDBSpec defines the InfluxDB endpoint metrics are reported to
+type InfluxDBSpec struct {
+	// Uri is the InfluxDB endpoint, e.g. "http://influxdb:8086"
+	Uri string `json:"uri"`
+
+	// Database is the InfluxDB database metrics are written to
+	Database string `json:"database,omitempty"`
+
+	// CredentialsSecret is the name of a secret holding the InfluxDB
+	// credentials under the "username" and "password" keys. They are
+	// injected as env vars and never written to the config maps
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// ExtraConfigSpec mounts an existing ConfigMap or Secret as config files on
+// both the controller and segment store pods, e.g. core-site.xml for HDFS
+// tier-2 or krb5.conf for Kerberos. Exactly one of ConfigMap and Secret
+// must be set
+type ExtraConfigSpec struct {
+	// Name of the volume
+	Name string `json:"name"`
+
+	// ConfigMap is the name of the config map to mount
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// Secret is the name of the secret to mount
+	Secret string `json:"secret,omitempty"`
+
+	// MountPath is the directory the entries are mounted at
+	MountPath string `json:"mountPath"`
+}
+
+// ServiceAccountTokenSpec describes a projected service-account token volume.
+// The token is written to a file named "token" under the mount path
+type ServiceAccountTokenSpec struct {
+	// Name of the volume
+	Name string `json:"name"`
+
+	// Audience is the intended audience of the token
+	Audience string `json:"audience,omitempty"`
+
+	// ExpirationSeconds is the requested lifetime of the token.
+	// If not specified, the Kubernetes default of 1 hour is used
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+
+	// MountPath is the directory the token volume is mounted at
+	MountPath string `json:"mountPath"`
 }
 
-func (s *PravegaSpec) withDefaults() (changed bool) {
+// ProbeSpec overrides selected timing parameters of a probe generated by the
+// operator. Fields left to zero keep the operator default
+type ProbeSpec struct {
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       int32 `json:"periodSeconds,omitempty"`
+	TimeoutSeconds      int32 `json:"timeoutSeconds,omitempty"`
+	SuccessThreshold    int32 `json:"successThreshold,omitempty"`
+	FailureThreshold    int32 `json:"failureThreshold,omitempty"`
+}
+
+func (s *PravegaSpec) withDefaults(clusterName string) (changed bool) {
 	if !config.TestMode && s.ControllerReplicas < 1 {
 		changed = true
 		s.ControllerReplicas = 1
@@ -139,6 +726,17 @@ func (s *PravegaSpec) withDefaults() (changed bool) {
 		s.Options = map[string]string{}
 	}
 
+	if s.CreateServiceAccounts {
+		if s.ControllerServiceAccountName == "" {
+			changed = true
+			s.ControllerServiceAccountName = fmt.Sprintf("%s-pravega-controller", clusterName)
+		}
+		if s.SegmentStoreServiceAccountName == "" {
+			changed = true
+			s.SegmentStoreServiceAccountName = fmt.Sprintf("%s-pravega-segmentstore", clusterName)
+		}
+	}
+
 	if s.CacheVolumeClaimTemplate == nil {
 		changed = true
 		s.CacheVolumeClaimTemplate = &v1.PersistentVolumeClaimSpec{
@@ -212,6 +810,27 @@ func (s *PravegaImageSpec) withDefaults() (changed bool) {
 	return changed
 }
 
+// AuditLogSpec routes the controller audit trail
+type AuditLogSpec struct {
+	// Enabled turns on the audit appender
+	Enabled bool `json:"enabled"`
+
+	// Format is the audit record format: "json" emits one structured
+	// record per line for machine ingestion, "text" a human-readable one.
+	// By default, records are JSON
+	Format string `json:"format,omitempty"`
+
+	// Volume, when set, makes the audit trail go to rotated files on this
+	// volume instead of stdout, for pipelines that collect log files from
+	// a shared volume
+	Volume *v1.VolumeSource `json:"volume,omitempty"`
+}
+
+// IsEnabled returns whether the audit appender is configured
+func (al *AuditLogSpec) IsEnabled() bool {
+	return al != nil && al.Enabled
+}
+
 // Tier2Spec configures the Tier 2 storage type to use with Pravega.
 // If not specified, Tier 2 will be configured in filesystem mode and will try
 // to use a PersistentVolumeClaim with the name "pravega-tier2"
@@ -226,6 +845,13 @@ type Tier2Spec struct {
 
 	// Hdfs is used to configure an HDFS system as a Tier 2 backend
 	Hdfs *HDFSSpec `json:"hdfs,omitempty"`
+
+	// AmbientCredentials makes the storage binding rely on the cloud
+	// provider credentials chain (IRSA, GKE Workload Identity, Azure
+	// Workload Identity) instead of a static credentials secret. Requires
+	// workload identity to be configured on the segment store service
+	// account
+	AmbientCredentials bool `json:"ambientCredentials,omitempty"`
 }
 
 func (s *Tier2Spec) withDefaults() (changed bool) {
@@ -254,6 +880,16 @@ type ECSSpec struct {
 	Root        string `json:"root"`
 	Namespace   string `json:"namespace"`
 	Credentials string `json:"credentials"`
+
+	// ServerSideEncryption asks the storage system to encrypt Pravega's
+	// objects at rest: "sse-s3" for provider-managed keys or "sse-kms"
+	// for a customer-managed key named in KmsKeyId.
+	// By default, the bucket's own encryption settings apply
+	ServerSideEncryption string `json:"serverSideEncryption,omitempty"`
+
+	// KmsKeyId identifies the KMS key to encrypt with when
+	// ServerSideEncryption is "sse-kms"
+	KmsKeyId string `json:"kmsKeyId,omitempty"`
 }
 
 // HDFSSpec contains the connection details to an HDFS system
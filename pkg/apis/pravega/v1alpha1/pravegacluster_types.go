@@ -11,7 +11,12 @@
 package v1alpha1
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -52,9 +57,151 @@ type PravegaCluster struct {
 	Status ClusterStatus `json:"status,omitempty"`
 }
 
+// UpgradePausedAnnotation pauses the upgrade and rollback state machines
+// between pods when set to "true" on the PravegaCluster, so an anomaly
+// can be investigated mid-rollout without the operator charging ahead.
+// Removing the annotation resumes where the rollout left off
+const UpgradePausedAnnotation = "pravega.io/upgrade-paused"
+
+// IsUpgradePaused returns whether the upgrade state machine is paused
+// through the UpgradePausedAnnotation
+func (p *PravegaCluster) IsUpgradePaused() bool {
+	return p.Annotations[UpgradePausedAnnotation] == "true"
+}
+
+// SkipVersionCheckAnnotation bypasses the webhook's version transition
+// validation when set to "true" on the PravegaCluster, admitting version
+// jumps and downgrades the compatibility matrix would reject. Meant for
+// experts who have verified the transition out of band; an unsupported
+// transition can leave the cluster unable to read its own metadata
+const SkipVersionCheckAnnotation = "pravega.io/skip-version-check"
+
+// OptionRewritesAnnotation carries the option migrations the admission
+// webhook applied to the spec, as a comma-separated list of
+// "old -> new" entries. The webhook cannot write the status subresource,
+// so the reconciler copies the entries into status.optionRewrites from
+// here
+const OptionRewritesAnnotation = "pravega.io/option-rewrites"
+
+// UpgradeSnapshotSpec describes the Job that snapshots the cluster's
+// ZooKeeper subtree before an upgrade starts. The operator passes the
+// connection string, the subtree root and the artifact path to the Job
+// container in the ZOOKEEPER_URI, SNAPSHOT_PATH and SNAPSHOT_FILE
+// environment variables; the image brings the snapshot tooling
+type UpgradeSnapshotSpec struct {
+	// Enabled makes upgrades wait for the snapshot Job to complete
+	// before the first pod moves
+	Enabled bool `json:"enabled"`
+
+	// Image is the image run by the snapshot Job
+	Image string `json:"image"`
+
+	// Command overrides the entrypoint of the snapshot image
+	Command []string `json:"command,omitempty"`
+
+	// Volume is mounted at /snapshot on the Job pod and receives the
+	// snapshot artifact. Without it the artifact only lives in the Job
+	// pod's own filesystem
+	Volume *v1.VolumeSource `json:"volume,omitempty"`
+}
+
+// IsEnabled returns whether the pre-upgrade snapshot Job is configured
+func (us *UpgradeSnapshotSpec) IsEnabled() bool {
+	return us != nil && us.Enabled
+}
+
+// MaintenanceWindowSpec describes a recurring time window during which
+// disruptive operations may run
+type MaintenanceWindowSpec struct {
+	// DaysOfWeek lists the weekdays the window opens on, by full name or
+	// three-letter abbreviation, e.g. "Saturday" or "Sat".
+	// An empty list opens the window every day
+	DaysOfWeek []string `json:"daysOfWeek,omitempty"`
+
+	// Start is the opening time of the window in 24-hour "HH:MM" form
+	Start string `json:"start"`
+
+	// DurationMinutes is how long the window stays open
+	DurationMinutes int32 `json:"durationMinutes"`
+
+	// TimeZone is the IANA time zone the schedule is evaluated in, e.g.
+	// "Europe/Berlin".
+	// Defaults to UTC
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Contains returns whether the given instant falls inside the window. A
+// window that does not parse counts as closed; the webhook rejects such
+// windows on admission
+func (mw *MaintenanceWindowSpec) Contains(t time.Time) (bool, error) {
+	loc := time.UTC
+	if mw.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(mw.TimeZone); err != nil {
+			return false, fmt.Errorf("unknown time zone %q: %v", mw.TimeZone, err)
+		}
+	}
+	start, err := time.Parse("15:04", mw.Start)
+	if err != nil {
+		return false, fmt.Errorf("malformed window start %q: expected 24-hour \"HH:MM\"", mw.Start)
+	}
+
+	// The open window may have started today or, when it spans midnight,
+	// the day before
+	local := t.In(loc)
+	for _, dayOffset := range []int{0, -1} {
+		day := local.AddDate(0, 0, dayOffset)
+		if !mw.opensOn(day.Weekday()) {
+			continue
+		}
+		opens := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+		closes := opens.Add(time.Duration(mw.DurationMinutes) * time.Minute)
+		if !local.Before(opens) && local.Before(closes) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (mw *MaintenanceWindowSpec) opensOn(day time.Weekday) bool {
+	if len(mw.DaysOfWeek) == 0 {
+		return true
+	}
+	name := day.String()
+	for _, d := range mw.DaysOfWeek {
+		if strings.EqualFold(d, name) || strings.EqualFold(d, name[:3]) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidDayOfWeek returns whether the given day matches a weekday name
+// or its three-letter abbreviation
+func IsValidDayOfWeek(day string) bool {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := d.String()
+		if strings.EqualFold(day, name) || strings.EqualFold(day, name[:3]) {
+			return true
+		}
+	}
+	return false
+}
+
+// InMaintenanceWindow returns whether disruptive operations may run now.
+// Without a configured window they may run at any time; a misconfigured
+// window keeps them queued rather than running them at an arbitrary time
+func (p *PravegaCluster) InMaintenanceWindow() bool {
+	if p.Spec.MaintenanceWindow == nil {
+		return true
+	}
+	open, err := p.Spec.MaintenanceWindow.Contains(time.Now())
+	return err == nil && open
+}
+
 // WithDefaults set default values when not defined in the spec.
 func (p *PravegaCluster) WithDefaults() (changed bool) {
-	changed = p.Spec.withDefaults()
+	changed = p.Spec.withDefaults(p.Name)
 
 	return changed
 }
@@ -68,16 +215,76 @@ type ClusterSpec struct {
 	// available at: https://github.com/pravega/zookeeper-operator
 	ZookeeperUri string `json:"zookeeperUri"`
 
+	// Zookeeper carries the client-side ZooKeeper settings shared by all
+	// components, such as authentication against a shared ensemble.
+	// By default, the components connect unauthenticated
+	Zookeeper *ZookeeperSpec `json:"zookeeper,omitempty"`
+
 	// ExternalAccess specifies whether or not to allow external access
 	// to clients and the service type to use to achieve it
 	// By default, external access is not enabled
 	ExternalAccess *ExternalAccess `json:"externalAccess"`
 
+	// NetworkPolicy makes the operator create NetworkPolicies that restrict
+	// ingress to the cluster components to the traffic the operator itself
+	// wires up, plus the configured client CIDRs.
+	// By default, no NetworkPolicies are created
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// ServiceMesh adapts the generated resources to a service mesh.
+	// By default, no service mesh integration is applied
+	ServiceMesh *ServiceMeshSpec `json:"serviceMesh,omitempty"`
+
 	// TLS is the Pravega security configuration that is passed to the Pravega processes.
 	// See the following file for a complete list of options:
 	// https://github.com/pravega/pravega/blob/master/documentation/src/docs/security/pravega-security-configurations.md
 	TLS *TLSPolicy `json:"tls,omitempty"`
 
+	// Authentication is the Pravega auth configuration shared by the
+	// controller and segment store.
+	// By default, no authentication material is injected
+	Authentication *AuthenticationSpec `json:"authentication,omitempty"`
+
+	// SecretsProvider sources sensitive material from an external secrets
+	// manager instead of long-lived Kubernetes Secrets.
+	// By default, material comes from the secrets named elsewhere in the spec
+	SecretsProvider *SecretsProviderSpec `json:"secretsProvider,omitempty"`
+
+	// SecurityPolicy makes the operator create the policy object granting
+	// exactly what the Pravega pods need — a SecurityContextConstraints on
+	// OpenShift or a PodSecurityPolicy on plain Kubernetes — and bind it
+	// to the component service accounts, so installs do not require
+	// cluster-admin workarounds.
+	// By default, no policy objects are created
+	SecurityPolicy *SecurityPolicySpec `json:"securityPolicy,omitempty"`
+
+	// Profile expands into a sizing preset covering replicas, resources
+	// and cache sizes of all components. Options are "small", "medium"
+	// and "large". Explicit spec fields take precedence over the preset;
+	// the preset takes precedence over the built-in defaults.
+	// By default, no preset is applied
+	Profile string `json:"profile,omitempty"`
+
+	// DependencyWait moves the dependency check out of the Pravega
+	// entrypoint into an init container, so pods fail fast with clear
+	// events instead of crash-looping when a dependency is down.
+	// By default, the entrypoint's own WAIT_FOR loop is used
+	DependencyWait *DependencyWaitSpec `json:"dependencyWait,omitempty"`
+
+	// MaintenanceWindow restricts disruptive operations — upgrades,
+	// rollbacks, scale-downs and certificate or key rotations — to
+	// recurring time windows. Changes requested outside a window are
+	// queued and reported as pending in the status until it opens.
+	// By default, disruptive operations run as soon as they are requested
+	MaintenanceWindow *MaintenanceWindowSpec `json:"maintenanceWindow,omitempty"`
+
+	// UpgradeSnapshot makes upgrades run a Job that dumps the cluster's
+	// ZooKeeper subtree to a volume before the first pod moves, so a
+	// failed upgrade has a documented recovery point. The upgrade does
+	// not start until the Job has completed.
+	// By default, no snapshot is taken
+	UpgradeSnapshot *UpgradeSnapshotSpec `json:"upgradeSnapshot,omitempty"`
+
 	// Version is the expected version of the Pravega cluster.
 	// The pravega-operator will eventually make the Pravega cluster version
 	// equal to the expected version.
@@ -95,7 +302,7 @@ type ClusterSpec struct {
 	Pravega *PravegaSpec `json:"pravega"`
 }
 
-func (s *ClusterSpec) withDefaults() (changed bool) {
+func (s *ClusterSpec) withDefaults(clusterName string) (changed bool) {
 	if s.ZookeeperUri == "" {
 		changed = true
 		s.ZookeeperUri = DefaultZookeeperUri
@@ -117,6 +324,27 @@ func (s *ClusterSpec) withDefaults() (changed bool) {
 		}
 	}
 
+	// When cert-manager issues the certificates, the secret names are fixed
+	// up front so the TLS wiring can reference them before the secrets exist
+	if s.TLS.IsCertManagerEnabled() {
+		if s.TLS.Static == nil {
+			changed = true
+			s.TLS.Static = &StaticTLS{}
+		}
+		if s.TLS.Static.ControllerSecret == "" {
+			changed = true
+			s.TLS.Static.ControllerSecret = fmt.Sprintf("%s-pravega-controller-tls", clusterName)
+		}
+		if s.TLS.Static.SegmentStoreSecret == "" {
+			changed = true
+			s.TLS.Static.SegmentStoreSecret = fmt.Sprintf("%s-pravega-segmentstore-tls", clusterName)
+		}
+		if s.TLS.CertManager.IssuerRef.Kind == "" {
+			changed = true
+			s.TLS.CertManager.IssuerRef.Kind = "Issuer"
+		}
+	}
+
 	if s.Version == "" {
 		s.Version = DefaultPravegaVersion
 		changed = true
@@ -126,22 +354,111 @@ func (s *ClusterSpec) withDefaults() (changed bool) {
 		changed = true
 		s.Bookkeeper = &BookkeeperSpec{}
 	}
+
+	if s.Pravega == nil {
+		changed = true
+		s.Pravega = &PravegaSpec{}
+	}
+
+	// The preset must expand before the per-component defaults, so preset
+	// values win over the built-in defaults but never over explicit fields
+	if s.applyProfile() {
+		changed = true
+	}
+
 	if s.Bookkeeper.withDefaults() {
 		changed = true
 	}
 
-	if s.Pravega == nil {
+	if s.Pravega.withDefaults(clusterName) {
 		changed = true
-		s.Pravega = &PravegaSpec{}
 	}
 
-	if s.Pravega.withDefaults() {
+	return changed
+}
+
+// clusterProfile is the set of sizing knobs a preset expands into
+type clusterProfile struct {
+	controllerReplicas   int32
+	segmentStoreReplicas int32
+	bookkeeperReplicas   int32
+	controllerCPU        string
+	controllerMemory     string
+	segmentStoreCPU      string
+	segmentStoreMemory   string
+	cacheSize            string
+}
+
+// clusterProfiles maps the sizing presets to the settings they expand
+// into. The JVM heap and direct memory follow from the memory limits
+var clusterProfiles = map[string]clusterProfile{
+	"small":  {1, 1, 3, "500m", "1Gi", "1", "4Gi", "20Gi"},
+	"medium": {2, 3, 3, "1", "2Gi", "2", "8Gi", "40Gi"},
+	"large":  {3, 6, 5, "2", "3Gi", "4", "16Gi", "80Gi"},
+}
+
+// IsValidProfile returns whether the given name is a known sizing preset
+func IsValidProfile(name string) bool {
+	_, ok := clusterProfiles[name]
+	return ok
+}
+
+func (s *ClusterSpec) applyProfile() (changed bool) {
+	profile, ok := clusterProfiles[s.Profile]
+	if !ok {
+		return false
+	}
+
+	if s.Pravega.ControllerReplicas == 0 {
+		changed = true
+		s.Pravega.ControllerReplicas = profile.controllerReplicas
+	}
+	if s.Pravega.SegmentStoreReplicas == 0 {
+		changed = true
+		s.Pravega.SegmentStoreReplicas = profile.segmentStoreReplicas
+	}
+	if s.Bookkeeper.Replicas == 0 {
+		changed = true
+		s.Bookkeeper.Replicas = profile.bookkeeperReplicas
+	}
+
+	if s.Pravega.ControllerResources == nil {
+		changed = true
+		s.Pravega.ControllerResources = profileResources(profile.controllerCPU, profile.controllerMemory)
+	}
+	if s.Pravega.SegmentStoreResources == nil {
+		changed = true
+		s.Pravega.SegmentStoreResources = profileResources(profile.segmentStoreCPU, profile.segmentStoreMemory)
+	}
+
+	if s.Pravega.CacheVolumeClaimTemplate == nil {
 		changed = true
+		s.Pravega.CacheVolumeClaimTemplate = &v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: resource.MustParse(profile.cacheSize),
+				},
+			},
+		}
 	}
 
 	return changed
 }
 
+func profileResources(cpu string, memory string) *v1.ResourceRequirements {
+	return &v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		},
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		},
+	}
+}
+
 // ExternalAccess defines the configuration of the external access
 type ExternalAccess struct {
 	// Enabled specifies whether or not external access is enabled
@@ -152,13 +469,102 @@ type ExternalAccess struct {
 	// Options are "LoadBalancer" and "NodePort".
 	// By default, if external access is enabled, it will use "LoadBalancer"
 	Type v1.ServiceType `json:"type,omitempty"`
+
+	// ControllerEnabled overrides Enabled for the controller service, so the
+	// controller can stay internal while segment stores are exposed, or the
+	// other way around
+	ControllerEnabled *bool `json:"controllerEnabled,omitempty"`
+
+	// SegmentStoreEnabled overrides Enabled for the per-pod segment store
+	// services
+	SegmentStoreEnabled *bool `json:"segmentStoreEnabled,omitempty"`
+
+	// Profile expands into the cloud-specific service annotations of a
+	// common load balancer setup. Options are "aws-nlb", "gcp-internal"
+	// and "azure-internal". Explicit annotations take precedence over the
+	// ones a profile expands to
+	Profile string `json:"profile,omitempty"`
+
+	// Annotations to be added to the external services, typically used to
+	// select cloud load balancer flavors (internal, NLB, idle timeouts, ...)
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// LoadBalancerSourceRanges restricts the client IP ranges that may reach
+	// the external services. Only applies when the type is "LoadBalancer".
+	// By default, traffic is allowed from any source
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+
+	// ExternalTrafficPolicy denotes whether the external services route
+	// traffic to node-local or cluster-wide endpoints. Options are "Local"
+	// and "Cluster".
+	// By default, the controller service uses "Cluster" and the per-pod
+	// segment store services use "Local"
+	ExternalTrafficPolicy v1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+
+	// LoadBalancerIP requests a specific IP for the controller service.
+	// Only applies when the type is "LoadBalancer" and the cloud provider
+	// or load balancer implementation (e.g. MetalLB) supports it
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+
+	// SegmentStoreLoadBalancerIPs requests specific IPs for the per-pod
+	// segment store services. Segment store pod N is assigned the N-th IP
+	// of the list; pods beyond the end of the list get a dynamic IP
+	SegmentStoreLoadBalancerIPs []string `json:"segmentStoreLoadBalancerIPs,omitempty"`
+
+	// DomainName, when specified, makes the operator annotate the controller
+	// and per-pod segment store services with external-dns hostnames of the
+	// form "<service-name>.<domainName>", so DNS records are kept in sync
+	// with the provisioned load balancers
+	DomainName string `json:"domainName,omitempty"`
+
+	// Ingress makes the operator create an Ingress routing to the
+	// controller REST endpoint
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+
+	// Routes makes the operator create OpenShift Routes for the controller
+	// REST and gRPC endpoints. Only meaningful on OpenShift clusters
+	Routes *RoutesSpec `json:"routes,omitempty"`
+}
+
+// RoutesSpec defines the OpenShift Routes generated for the controller.
+// The gRPC route uses passthrough TLS termination since gRPC traffic
+// cannot be re-encrypted at the router
+type RoutesSpec struct {
+	// RestHost is the DNS name routed to the controller REST endpoint.
+	// If not specified, the router assigns a default host
+	RestHost string `json:"restHost,omitempty"`
+
+	// GrpcHost is the DNS name routed to the controller gRPC endpoint.
+	// If not specified, the router assigns a default host
+	GrpcHost string `json:"grpcHost,omitempty"`
+
+	// Annotations to be added to the generated Routes
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// IngressSpec defines the Ingress generated for the controller REST API
+type IngressSpec struct {
+	// Host is the DNS name routed to the controller REST endpoint
+	Host string `json:"host"`
+
+	// TLSSecret is the name of the secret used to terminate TLS for the
+	// host. If not specified, the Ingress serves plain HTTP
+	TLSSecret string `json:"tlsSecret,omitempty"`
+
+	// IngressClass selects the ingress controller implementation through
+	// the "kubernetes.io/ingress.class" annotation
+	IngressClass string `json:"ingressClass,omitempty"`
+
+	// Annotations to be added to the generated Ingress
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 func (e *ExternalAccess) withDefaults() (changed bool) {
-	if e.Enabled == false && e.Type != "" {
+	enabled := e.IsEnabledForController() || e.IsEnabledForSegmentStore()
+	if enabled == false && e.Type != "" {
 		changed = true
 		e.Type = ""
-	} else if e.Enabled == true && e.Type == "" {
+	} else if enabled == true && e.Type == "" {
 		changed = true
 		e.Type = DefaultServiceType
 	}
@@ -166,21 +572,384 @@ func (e *ExternalAccess) withDefaults() (changed bool) {
 	return changed
 }
 
+// IsEnabledForController returns whether the controller service is exposed
+func (e *ExternalAccess) IsEnabledForController() bool {
+	if e.ControllerEnabled != nil {
+		return *e.ControllerEnabled
+	}
+	return e.Enabled
+}
+
+// IsEnabledForSegmentStore returns whether the per-pod segment store
+// services are exposed
+func (e *ExternalAccess) IsEnabledForSegmentStore() bool {
+	if e.SegmentStoreEnabled != nil {
+		return *e.SegmentStoreEnabled
+	}
+	return e.Enabled
+}
+
+// ServiceMeshSpec defines the service mesh integrations of the cluster
+type ServiceMeshSpec struct {
+	// Istio enables the Istio compatibility mode: service ports are renamed
+	// with protocol prefixes, sidecar injection is tuned so pods start and
+	// probe correctly, and (optionally) the operator's own TLS is skipped in
+	// favor of mesh mTLS
+	Istio *IstioSpec `json:"istio,omitempty"`
+}
+
+// IstioSpec defines the Istio compatibility mode
+type IstioSpec struct {
+	// Enabled turns on the Istio compatibility mode
+	Enabled bool `json:"enabled"`
+
+	// MutualTLS indicates that the mesh encrypts pod-to-pod traffic, so the
+	// operator does not configure Pravega's own TLS even when TLS secrets
+	// are present in the spec
+	MutualTLS bool `json:"mutualTLS,omitempty"`
+}
+
+// IsIstioEnabled returns whether the Istio compatibility mode is on
+func (s *ClusterSpec) IsIstioEnabled() bool {
+	return s.ServiceMesh != nil && s.ServiceMesh.Istio != nil && s.ServiceMesh.Istio.Enabled
+}
+
+// IsMeshMTLSEnabled returns whether mesh mTLS replaces Pravega's own TLS
+func (s *ClusterSpec) IsMeshMTLSEnabled() bool {
+	return s.IsIstioEnabled() && s.ServiceMesh.Istio.MutualTLS
+}
+
+// NetworkPolicySpec defines the NetworkPolicies generated for the cluster
+type NetworkPolicySpec struct {
+	// ClientCIDRs are additional CIDR blocks allowed to reach the controller
+	// and segment store client ports.
+	// If empty, only in-cluster Pravega components can connect
+	ClientCIDRs []string `json:"clientCIDRs,omitempty"`
+}
+
+// DependencyWaitSpec defines the init-container-based dependency check
+type DependencyWaitSpec struct {
+	// Enabled turns on the wait init container
+	Enabled bool `json:"enabled"`
+
+	// TimeoutSeconds is how long the init container waits for the
+	// dependencies before failing the pod.
+	// By default, it waits for 5 minutes
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// ExtraEndpoints are additional "host:port" endpoints to wait for,
+	// e.g. a tier-2 NFS server or an external BookKeeper ensemble
+	ExtraEndpoints []string `json:"extraEndpoints,omitempty"`
+
+	// Image is the image the wait init container runs. It must provide
+	// "/bin/sh" and "nc".
+	// By default, a busybox image is used
+	Image string `json:"image,omitempty"`
+}
+
+// IsEnabled returns whether the wait init container replaces the
+// entrypoint's WAIT_FOR loop
+func (dw *DependencyWaitSpec) IsEnabled() bool {
+	return dw != nil && dw.Enabled
+}
+
+// ZookeeperSpec defines how the Pravega components connect to the
+// ZooKeeper ensemble
+type ZookeeperSpec struct {
+	// Auth makes all components authenticate their ZooKeeper sessions,
+	// as shared ensembles commonly require
+	Auth *ZookeeperAuthSpec `json:"auth,omitempty"`
+}
+
+// ZookeeperAuthSpec defines the ZooKeeper client authentication material
+type ZookeeperAuthSpec struct {
+	// Scheme is the ZooKeeper authentication scheme, either "digest" or
+	// "sasl"
+	Scheme string `json:"scheme"`
+
+	// CredentialsSecret is the name of a secret holding the client
+	// credentials. For "digest" it holds the "username" and "password"
+	// keys; for "sasl" it holds a JAAS configuration with a Client
+	// section under the "jaas.conf" key
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// IsAuthEnabled returns whether the components authenticate their
+// ZooKeeper sessions
+func (zs *ZookeeperSpec) IsAuthEnabled() bool {
+	return zs != nil && zs.Auth != nil && zs.Auth.CredentialsSecret != ""
+}
+
+// AuthenticationSpec defines the auth material shared by the Pravega
+// components
+type AuthenticationSpec struct {
+	// Enabled turns on authorization on the controller and segment stores.
+	// Requires a token signing key and a user database (a password file or
+	// a custom auth-handler plugin) to be configured
+	Enabled bool `json:"enabled"`
+
+	// TokenSigningKeySecret is the name of a secret holding the token
+	// signing key under the "TOKEN_SIGNING_KEY" key. The key is injected
+	// into both the controller and segment store environments so that
+	// tokens issued by one component validate on the others
+	TokenSigningKeySecret string `json:"tokenSigningKeySecret,omitempty"`
+
+	// NextTokenSigningKeySecret stages a replacement signing key with the
+	// same layout as TokenSigningKeySecret, starting a rotation. The
+	// segment stores first learn the new key alongside the old one, then
+	// the controller switches issuance over, and finally the old key is
+	// retired — a plain key swap instead invalidates every outstanding
+	// token at once. Completion is reported in
+	// status.activeTokenSigningKey; promote the staged secret to
+	// TokenSigningKeySecret and clear this field afterwards
+	NextTokenSigningKeySecret string `json:"nextTokenSigningKeySecret,omitempty"`
+
+	// PasswordAuthSecret is the name of a secret holding the Pravega
+	// password file under the "passwd" key. The file is mounted on the
+	// controller pods and PasswordAuthHandler is enabled against it
+	PasswordAuthSecret string `json:"passwordAuthSecret,omitempty"`
+
+	// DelegationTokenTTLSeconds is the lifetime of the delegation tokens
+	// the controller issues. -1 disables expiry. Too short a value breaks
+	// long-running readers; -1 issued tokens never invalidate.
+	// By default, the Pravega built-in TTL is used
+	DelegationTokenTTLSeconds *int32 `json:"delegationTokenTTLSeconds,omitempty"`
+
+	// TokenRefreshThresholdSeconds is how long before expiry clients are
+	// expected to refresh their delegation tokens. Must be smaller than
+	// the token TTL
+	TokenRefreshThresholdSeconds *int32 `json:"tokenRefreshThresholdSeconds,omitempty"`
+
+	// PluginImage is an image carrying custom auth-handler JARs under
+	// "/plugins". An init container copies them into the controller's
+	// plugin directory before startup, so handlers such as Keycloak or
+	// LDAP do not require a custom Pravega image
+	PluginImage string `json:"pluginImage,omitempty"`
+
+	// PluginSecret is the name of a secret whose entries are auth-handler
+	// JARs, mounted directly into the controller's plugin directory.
+	// Suitable for small, self-contained handlers
+	PluginSecret string `json:"pluginSecret,omitempty"`
+
+	// Keycloak configures the Pravega Keycloak auth handler on the
+	// controller and segment stores. The handler JARs themselves still
+	// come in through PluginImage or PluginSecret
+	Keycloak *KeycloakSpec `json:"keycloak,omitempty"`
+}
+
+// KeycloakSpec wires the Keycloak auth handler to a realm and client
+type KeycloakSpec struct {
+	// Realm is the Keycloak realm the Pravega clients belong to
+	Realm string `json:"realm"`
+
+	// ClientId is the Keycloak client representing the Pravega cluster
+	ClientId string `json:"clientId"`
+
+	// CredentialsSecret is the name of a secret holding the service
+	// account JSON config under the "keycloak.json" key. It is mounted on
+	// both components and the handler is pointed at it
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// IsKeycloakEnabled returns whether the Keycloak auth handler is configured
+func (as *AuthenticationSpec) IsKeycloakEnabled() bool {
+	return as != nil && as.Keycloak != nil && as.Keycloak.CredentialsSecret != ""
+}
+
+// IsEnabled returns whether authorization is turned on
+func (as *AuthenticationSpec) IsEnabled() bool {
+	return as != nil && as.Enabled
+}
+
+// SecurityPolicySpec selects the pod security policy object the operator
+// creates and binds for the Pravega pods
+type SecurityPolicySpec struct {
+	// Type selects the policy flavor: "scc" on OpenShift or "psp" on
+	// plain Kubernetes
+	Type string `json:"type"`
+
+	// FsGroup is applied to the pod security contexts so the processes
+	// can write their PersistentVolumeClaims.
+	// By default, no fsGroup is set
+	FsGroup *int64 `json:"fsGroup,omitempty"`
+}
+
+// IsEnabled returns whether a policy object should be created
+func (sps *SecurityPolicySpec) IsEnabled() bool {
+	return sps != nil && (sps.Type == "scc" || sps.Type == "psp")
+}
+
+// SecretsProviderSpec selects an external secrets manager. Only Vault is
+// supported today
+type SecretsProviderSpec struct {
+	Vault *VaultSpec `json:"vault,omitempty"`
+}
+
+// VaultSpec configures the Vault agent injector. The operator stamps the
+// injector annotations on the pods; the injected agent authenticates with
+// the given role and renders the configured paths as files under
+// /vault/secrets, which the generated configuration references by path
+type VaultSpec struct {
+	// Role is the Vault role the pods authenticate as
+	Role string `json:"role"`
+
+	// TLSPath is the KV path holding the TLS material under the
+	// "certificate", "private_key" and "issuing_ca" fields. When set, the
+	// listeners use the rendered files instead of a mounted secret
+	TLSPath string `json:"tlsPath,omitempty"`
+
+	// TokenSigningKeyPath is the KV path holding the token signing key
+	// under the "key" field, rendered to /vault/secrets/token-signing-key
+	TokenSigningKeyPath string `json:"tokenSigningKeyPath,omitempty"`
+
+	// Tier2CredentialsPath is the KV path holding the tier-2 storage
+	// credentials, rendered field-per-line to
+	// /vault/secrets/tier2-credentials for the storage options to
+	// reference by path
+	Tier2CredentialsPath string `json:"tier2CredentialsPath,omitempty"`
+}
+
+// IsVaultEnabled returns whether the pods pull sensitive material from
+// Vault
+func (sp *SecretsProviderSpec) IsVaultEnabled() bool {
+	return sp != nil && sp.Vault != nil && sp.Vault.Role != ""
+}
+
 type TLSPolicy struct {
 	// Static TLS means keys/certs are generated by the user and passed to an operator.
 	Static *StaticTLS `json:"static,omitempty"`
+
+	// CertManager delegates certificate issuance to cert-manager. The
+	// operator creates a Certificate per component and wires the resulting
+	// secrets into the pods, so no static secrets need to be pre-created
+	CertManager *CertManagerTLS `json:"certManager,omitempty"`
+
+	// CaBundleSecret names a secret holding the PEM CA bundle under the
+	// "ca.crt" key. The bundle is mounted on every component and converted
+	// into a JKS truststore at pod start, so controllers, segment stores
+	// and internal clients all trust the same CA chain
+	CaBundleSecret string `json:"caBundleSecret,omitempty"`
+
+	// KeystorePasswordSecret names a secret holding the keystore password
+	// under the "password" key. The password is injected as an env var and
+	// referenced from the JVM options, so it never lands in a config map
+	KeystorePasswordSecret string `json:"keystorePasswordSecret,omitempty"`
+
+	// TruststorePasswordSecret names a secret holding the truststore
+	// password under the "password" key. It protects the truststore
+	// generated from the CA bundle instead of the built-in default
+	TruststorePasswordSecret string `json:"truststorePasswordSecret,omitempty"`
+
+	// MinVersion is the lowest TLS protocol version the REST and gRPC
+	// listeners accept: "TLSv1.1", "TLSv1.2" or "TLSv1.3". Older
+	// protocols stay enabled when unset
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// CipherSuites restricts the cipher suites offered by the listeners
+	// to the given JSSE suite names
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+
+	// Bookkeeper encrypts the hop between the segment stores' BookKeeper
+	// client and the bookies, which otherwise stays in the clear even on
+	// fully TLS-enabled clusters
+	Bookkeeper *BookkeeperTLS `json:"bookkeeper,omitempty"`
+}
+
+// BookkeeperTLS configures TLS between the segment stores and the bookies
+type BookkeeperTLS struct {
+	// Enabled turns on TLS on the bookie listeners and the segment
+	// stores' BookKeeper client
+	Enabled bool `json:"enabled"`
+
+	// BookieSecret is the name of a secret holding the bookie server
+	// certificate under the "tls.crt" and "tls.key" keys and the issuing
+	// CA under "ca.crt". The CA is distributed to the segment stores so
+	// their client trusts the bookies
+	BookieSecret string `json:"bookieSecret"`
+
+	// ClientAuthentication makes the bookies require a client
+	// certificate; the segment stores present the one from
+	// static.segmentStoreSecret
+	ClientAuthentication bool `json:"clientAuthentication,omitempty"`
+}
+
+type CertManagerTLS struct {
+	// IssuerRef names the cert-manager Issuer or ClusterIssuer that signs
+	// the controller and segment store certificates
+	IssuerRef IssuerRef `json:"issuerRef"`
+
+	// ExtraDnsNames are added to the certificates besides the generated
+	// service names, e.g. hostnames published through external access
+	ExtraDnsNames []string `json:"extraDnsNames,omitempty"`
+}
+
+type IssuerRef struct {
+	Name string `json:"name"`
+
+	// Kind is "Issuer" (default) or "ClusterIssuer"
+	Kind string `json:"kind,omitempty"`
 }
 
 type StaticTLS struct {
 	ControllerSecret   string `json:"controllerSecret,omitempty"`
 	SegmentStoreSecret string `json:"segmentStoreSecret,omitempty"`
+
+	// ControllerRestSecret holds the TLS material of the controller REST
+	// listener when it must differ from the gRPC one, e.g. when REST TLS is
+	// terminated at an Ingress or Route while gRPC stays end-to-end
+	ControllerRestSecret string `json:"controllerRestSecret,omitempty"`
+
+	// ControllerGrpcSecret holds the TLS material of the controller gRPC
+	// listener when it must differ from the REST one
+	ControllerGrpcSecret string `json:"controllerGrpcSecret,omitempty"`
+}
+
+// tlsProtocolLadder lists the supported protocol versions from oldest to
+// newest, so a minimum version expands into itself and everything above it
+var tlsProtocolLadder = []string{"TLSv1.1", "TLSv1.2", "TLSv1.3"}
+
+// IsValidTLSProtocolVersion returns whether name is a protocol version the
+// listeners can be pinned to
+func IsValidTLSProtocolVersion(name string) bool {
+	return len(TLSProtocolVersionsFrom(name)) > 0
+}
+
+// TLSProtocolVersionsFrom expands a minimum protocol version into the list
+// of versions the listeners should accept
+func TLSProtocolVersionsFrom(minVersion string) []string {
+	for i, version := range tlsProtocolLadder {
+		if version == minVersion {
+			return tlsProtocolLadder[i:]
+		}
+	}
+	return nil
+}
+
+// IsCaBundleEnabled returns whether a shared CA bundle is distributed to
+// the pods
+func (tp *TLSPolicy) IsCaBundleEnabled() bool {
+	return tp != nil && tp.CaBundleSecret != ""
+}
+
+// IsBookkeeperTLSEnabled returns whether the segment store to bookie hop
+// is encrypted
+func (tp *TLSPolicy) IsBookkeeperTLSEnabled() bool {
+	return tp != nil && tp.Bookkeeper != nil && tp.Bookkeeper.Enabled && tp.Bookkeeper.BookieSecret != ""
+}
+
+// IsCertManagerEnabled returns whether the TLS secrets are issued by
+// cert-manager instead of being pre-created by the user
+func (tp *TLSPolicy) IsCertManagerEnabled() bool {
+	return tp != nil && tp.CertManager != nil && tp.CertManager.IssuerRef.Name != ""
 }
 
 func (tp *TLSPolicy) IsSecureController() bool {
 	if tp == nil || tp.Static == nil {
 		return false
 	}
-	return len(tp.Static.ControllerSecret) != 0
+	return len(tp.Static.ControllerSecret) != 0 ||
+		len(tp.Static.ControllerRestSecret) != 0 ||
+		len(tp.Static.ControllerGrpcSecret) != 0
 }
 
 func (tp *TLSPolicy) IsSecureSegmentStore() bool {
@@ -197,5 +966,15 @@ type ImageSpec struct {
 	// Deprecated: Use `spec.Version` instead
 	Tag string `json:"tag,omitempty"`
 
+	// Digest pins the image by content digest, e.g. "sha256:<hex>". When
+	// set, pods reference "<repository>@<digest>" instead of the version
+	// tag, for supply-chain policies that block tag-based deploys. The
+	// digest must be updated together with the version on upgrades
+	Digest string `json:"digest,omitempty"`
+
 	PullPolicy v1.PullPolicy `json:"pullPolicy"`
+
+	// PullSecrets is the list of secrets in the same namespace used to pull
+	// the image from a private registry
+	PullSecrets []v1.LocalObjectReference `json:"pullSecrets,omitempty"`
 }
@@ -12,6 +12,7 @@ package v1alpha1_test
 
 import (
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -68,4 +69,114 @@ var _ = Describe("PravegaCluster Types Spec", func() {
 			Ω(p.Spec.Bookkeeper).ShouldNot(BeNil())
 		})
 	})
+
+	Context("#MaintenanceWindowSpec.Contains", func() {
+		// 2020-01-04 was a Saturday
+		cases := []struct {
+			name    string
+			window  v1alpha1.MaintenanceWindowSpec
+			instant time.Time
+			open    bool
+			fails   bool
+		}{
+			{
+				name:    "inside the window, UTC by default",
+				window:  v1alpha1.MaintenanceWindowSpec{Start: "12:00", DurationMinutes: 60},
+				instant: time.Date(2020, 1, 4, 12, 30, 0, 0, time.UTC),
+				open:    true,
+			},
+			{
+				name:    "before the window opens",
+				window:  v1alpha1.MaintenanceWindowSpec{Start: "12:00", DurationMinutes: 60},
+				instant: time.Date(2020, 1, 4, 11, 59, 0, 0, time.UTC),
+				open:    false,
+			},
+			{
+				name:    "at the instant the window closes",
+				window:  v1alpha1.MaintenanceWindowSpec{Start: "12:00", DurationMinutes: 60},
+				instant: time.Date(2020, 1, 4, 13, 0, 0, 0, time.UTC),
+				open:    false,
+			},
+			{
+				name: "inside the window in a named zone",
+				window: v1alpha1.MaintenanceWindowSpec{
+					Start: "12:00", DurationMinutes: 60, TimeZone: "America/New_York",
+				},
+				// 12:30 in New York (UTC-5 in January)
+				instant: time.Date(2020, 1, 4, 17, 30, 0, 0, time.UTC),
+				open:    true,
+			},
+			{
+				name: "outside the window in a named zone",
+				window: v1alpha1.MaintenanceWindowSpec{
+					Start: "12:00", DurationMinutes: 60, TimeZone: "America/New_York",
+				},
+				// 12:30 UTC is only 07:30 in New York
+				instant: time.Date(2020, 1, 4, 12, 30, 0, 0, time.UTC),
+				open:    false,
+			},
+			{
+				name: "window spanning midnight, after midnight",
+				window: v1alpha1.MaintenanceWindowSpec{
+					DaysOfWeek: []string{"Saturday"}, Start: "23:00", DurationMinutes: 120,
+				},
+				// Sunday 00:30, inside the window that opened Saturday
+				instant: time.Date(2020, 1, 5, 0, 30, 0, 0, time.UTC),
+				open:    true,
+			},
+			{
+				name: "window spanning midnight does not open from an unlisted day",
+				window: v1alpha1.MaintenanceWindowSpec{
+					DaysOfWeek: []string{"Saturday"}, Start: "23:00", DurationMinutes: 120,
+				},
+				// Saturday 00:30 would need a window opened Friday
+				instant: time.Date(2020, 1, 4, 0, 30, 0, 0, time.UTC),
+				open:    false,
+			},
+			{
+				name: "day-of-week abbreviation matches",
+				window: v1alpha1.MaintenanceWindowSpec{
+					DaysOfWeek: []string{"sat", "sun"}, Start: "12:00", DurationMinutes: 60,
+				},
+				instant: time.Date(2020, 1, 4, 12, 30, 0, 0, time.UTC),
+				open:    true,
+			},
+			{
+				name: "unlisted day keeps the window closed",
+				window: v1alpha1.MaintenanceWindowSpec{
+					DaysOfWeek: []string{"Mon"}, Start: "12:00", DurationMinutes: 60,
+				},
+				instant: time.Date(2020, 1, 4, 12, 30, 0, 0, time.UTC),
+				open:    false,
+			},
+			{
+				name:    "malformed start",
+				window:  v1alpha1.MaintenanceWindowSpec{Start: "25:99", DurationMinutes: 60},
+				instant: time.Date(2020, 1, 4, 12, 30, 0, 0, time.UTC),
+				fails:   true,
+			},
+			{
+				name: "unknown time zone",
+				window: v1alpha1.MaintenanceWindowSpec{
+					Start: "12:00", DurationMinutes: 60, TimeZone: "Mars/Olympus_Mons",
+				},
+				instant: time.Date(2020, 1, 4, 12, 30, 0, 0, time.UTC),
+				fails:   true,
+			},
+		}
+
+		for _, c := range cases {
+			c := c
+			It(c.name, func() {
+				open, err := c.window.Contains(c.instant)
+				if c.fails {
+					Ω(err).ShouldNot(BeNil())
+					Ω(open).Should(BeFalse())
+				} else {
+					Ω(err).Should(BeNil())
+					Ω(open).Should(Equal(c.open))
+				}
+			})
+		}
+	})
 })
@@ -21,6 +21,7 @@ type ClusterConditionType string
 const (
 	ClusterConditionPodsReady ClusterConditionType = "PodsReady"
 	ClusterConditionUpgrading                      = "Upgrading"
+	ClusterConditionRollback                       = "RollingBack"
 	ClusterConditionError                          = "Error"
 )
 
@@ -36,6 +37,10 @@ type ClusterStatus struct {
 	// If the cluster is not upgrading, TargetVersion is empty.
 	TargetVersion string `json:"targetVersion,omitempty"`
 
+	// PreviousVersion is the version that was running before the last
+	// upgrade started. It is the version a failed upgrade rolls back to
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
 	// Replicas is the number of desired replicas in the cluster
 	Replicas int32 `json:"replicas"`
 
@@ -47,8 +52,79 @@ type ClusterStatus struct {
 
 	// Members is the Pravega members in the cluster
 	Members MembersStatus `json:"members"`
+
+	// ControllerLogLevel is the log level applied to the controller pods
+	ControllerLogLevel string `json:"controllerLogLevel,omitempty"`
+
+	// SegmentStoreLogLevel is the log level applied to the segment store
+	// pods
+	SegmentStoreLogLevel string `json:"segmentStoreLogLevel,omitempty"`
+
+	// OptionRewrites lists the deprecated Pravega options that were
+	// rewritten to their renamed counterparts during a version change
+	OptionRewrites []string `json:"optionRewrites,omitempty"`
+
+	// VersionHistory records the most recent version transitions of the
+	// cluster, upgrades and rollbacks alike, so their outcome can be read
+	// from the resource instead of the operator logs
+	VersionHistory []VersionTransition `json:"versionHistory,omitempty"`
+
+	// PendingMaintenance lists the disruptive changes currently queued
+	// until the maintenance window opens
+	PendingMaintenance []string `json:"pendingMaintenance,omitempty"`
+
+	// UpgradeSnapshotLocation is the artifact written by the pre-upgrade
+	// ZooKeeper snapshot Job of the most recent upgrade, relative to the
+	// configured snapshot volume
+	UpgradeSnapshotLocation string `json:"upgradeSnapshotLocation,omitempty"`
+
+	// ActiveTokenSigningKey is the secret the cluster currently signs
+	// delegation tokens with. When it equals the staged
+	// nextTokenSigningKeySecret, the rotation has completed and the spec
+	// can be updated to promote the new key
+	ActiveTokenSigningKey string `json:"activeTokenSigningKey,omitempty"`
+
+	// TlsCertHash is the digest of the mounted TLS secrets' content the
+	// pods are known to run with; a diverging digest rolls the pods and
+	// moves this forward
+	TlsCertHash string `json:"tlsCertHash,omitempty"`
+}
+
+// VersionTransition records one upgrade or rollback attempt
+type VersionTransition struct {
+	// FromVersion is the version the cluster ran when the transition started
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the version the transition moved towards
+	ToVersion string `json:"toVersion"`
+
+	// StartTime is the time the transition was triggered
+	StartTime string `json:"startTime,omitempty"`
+
+	// EndTime is the time the transition finished, successfully or not
+	EndTime string `json:"endTime,omitempty"`
+
+	// Outcome is one of "InProgress", "Completed", "Failed" and "Cancelled"
+	Outcome string `json:"outcome,omitempty"`
+
+	// Reason explains a "Failed" or "Cancelled" outcome
+	Reason string `json:"reason,omitempty"`
+
+	// FailedPods lists the pods that were unready when the transition failed
+	FailedPods []string `json:"failedPods,omitempty"`
 }
 
+const (
+	TransitionInProgress = "InProgress"
+	TransitionCompleted  = "Completed"
+	TransitionFailed     = "Failed"
+	TransitionCancelled  = "Cancelled"
+)
+
+// versionHistoryLimit bounds the history kept in the status; older entries
+// are dropped first
+const versionHistoryLimit = 10
+
 // MembersStatus is the status of the members of the cluster with both
 // ready and unready node membership lists
 type MembersStatus struct {
@@ -82,6 +158,7 @@ func (ps *ClusterStatus) InitConditions() {
 	conditionTypes := []ClusterConditionType{
 		ClusterConditionPodsReady,
 		ClusterConditionUpgrading,
+		ClusterConditionRollback,
 		ClusterConditionError,
 	}
 	for _, conditionType := range conditionTypes {
@@ -107,6 +184,28 @@ func (ps *ClusterStatus) SetUpgradingConditionTrue() {
 	ps.setClusterCondition(*c)
 }
 
+// UpdateProgress records which component is currently rolling and how far
+// it has come on whichever of the Upgrading or RollingBack conditions is
+// active, so the progress is visible on the condition instead of only in
+// the operator log
+func (ps *ClusterStatus) UpdateProgress(reason, message string) {
+	for _, conditionType := range []ClusterConditionType{ClusterConditionUpgrading, ClusterConditionRollback} {
+		if _, condition := ps.GetClusterCondition(conditionType); condition != nil && condition.Status == corev1.ConditionTrue {
+			ps.setClusterCondition(*newClusterCondition(conditionType, corev1.ConditionTrue, reason, message))
+		}
+	}
+}
+
+func (ps *ClusterStatus) SetRollbackConditionTrue(reason, message string) {
+	c := newClusterCondition(ClusterConditionRollback, corev1.ConditionTrue, reason, message)
+	ps.setClusterCondition(*c)
+}
+
+func (ps *ClusterStatus) SetRollbackConditionFalse() {
+	c := newClusterCondition(ClusterConditionRollback, corev1.ConditionFalse, "", "")
+	ps.setClusterCondition(*c)
+}
+
 func (ps *ClusterStatus) SetUpgradingConditionFalse() {
 	c := newClusterCondition(ClusterConditionUpgrading, corev1.ConditionFalse, "", "")
 	ps.setClusterCondition(*c)
@@ -122,6 +221,57 @@ func (ps *ClusterStatus) SetErrorConditionFalse() {
 	ps.setClusterCondition(*c)
 }
 
+// QueueMaintenance records that a change is held for the maintenance
+// window. The list is rebuilt on every reconcile, so entries disappear
+// once the change has been applied
+func (ps *ClusterStatus) QueueMaintenance(message string) {
+	for _, pending := range ps.PendingMaintenance {
+		if pending == message {
+			return
+		}
+	}
+	ps.PendingMaintenance = append(ps.PendingMaintenance, message)
+}
+
+// StartVersionTransition opens a history record for a transition to the
+// given version. A record that is already open for the same transition is
+// left untouched, so the caller may invoke this on every reconcile
+func (ps *ClusterStatus) StartVersionTransition(fromVersion, toVersion string) {
+	if n := len(ps.VersionHistory); n > 0 {
+		last := &ps.VersionHistory[n-1]
+		if last.Outcome == TransitionInProgress &&
+			last.FromVersion == fromVersion && last.ToVersion == toVersion {
+			return
+		}
+	}
+
+	ps.VersionHistory = append(ps.VersionHistory, VersionTransition{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		StartTime:   time.Now().Format(time.RFC3339),
+		Outcome:     TransitionInProgress,
+	})
+
+	if len(ps.VersionHistory) > versionHistoryLimit {
+		ps.VersionHistory = ps.VersionHistory[len(ps.VersionHistory)-versionHistoryLimit:]
+	}
+}
+
+// FinishVersionTransition closes the open history record with the given
+// outcome. It is a no-op when no transition is in progress
+func (ps *ClusterStatus) FinishVersionTransition(outcome, reason string, failedPods []string) {
+	n := len(ps.VersionHistory)
+	if n == 0 || ps.VersionHistory[n-1].Outcome != TransitionInProgress {
+		return
+	}
+
+	last := &ps.VersionHistory[n-1]
+	last.EndTime = time.Now().Format(time.RFC3339)
+	last.Outcome = outcome
+	last.Reason = reason
+	last.FailedPods = failedPods
+}
+
 func newClusterCondition(condType ClusterConditionType, status corev1.ConditionStatus, reason, message string) *ClusterCondition {
 	return &ClusterCondition{
 		Type:               condType,
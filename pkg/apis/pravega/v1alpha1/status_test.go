@@ -11,6 +11,8 @@
 package v1alpha1_test
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -81,4 +83,77 @@ var _ = Describe("PravegaCluster Status", func() {
 			})
 		})
 	})
+
+	Context("version history", func() {
+		Context("start a transition", func() {
+			BeforeEach(func() {
+				p.Status.StartVersionTransition("0.4.0", "0.5.0")
+			})
+
+			It("should open an in-progress record", func() {
+				Ω(p.Status.VersionHistory).Should(HaveLen(1))
+				Ω(p.Status.VersionHistory[0].FromVersion).Should(Equal("0.4.0"))
+				Ω(p.Status.VersionHistory[0].ToVersion).Should(Equal("0.5.0"))
+				Ω(p.Status.VersionHistory[0].Outcome).Should(Equal(v1alpha1.TransitionInProgress))
+				Ω(p.Status.VersionHistory[0].StartTime).ShouldNot(Equal(""))
+				Ω(p.Status.VersionHistory[0].EndTime).Should(Equal(""))
+			})
+
+			It("should leave the open record untouched when started again", func() {
+				p.Status.StartVersionTransition("0.4.0", "0.5.0")
+				Ω(p.Status.VersionHistory).Should(HaveLen(1))
+			})
+
+			It("should open a second record for a different transition", func() {
+				p.Status.FinishVersionTransition(v1alpha1.TransitionCompleted, "", nil)
+				p.Status.StartVersionTransition("0.5.0", "0.6.0")
+				Ω(p.Status.VersionHistory).Should(HaveLen(2))
+				Ω(p.Status.VersionHistory[1].FromVersion).Should(Equal("0.5.0"))
+			})
+		})
+
+		Context("finish a transition", func() {
+			BeforeEach(func() {
+				p.Status.StartVersionTransition("0.4.0", "0.5.0")
+				p.Status.FinishVersionTransition(v1alpha1.TransitionFailed, "pods unready", []string{"segmentstore-0"})
+			})
+
+			It("should close the open record", func() {
+				Ω(p.Status.VersionHistory).Should(HaveLen(1))
+				Ω(p.Status.VersionHistory[0].Outcome).Should(Equal(v1alpha1.TransitionFailed))
+				Ω(p.Status.VersionHistory[0].Reason).Should(Equal("pods unready"))
+				Ω(p.Status.VersionHistory[0].FailedPods).Should(Equal([]string{"segmentstore-0"}))
+				Ω(p.Status.VersionHistory[0].EndTime).ShouldNot(Equal(""))
+			})
+
+			It("should not touch a record that is already closed", func() {
+				p.Status.FinishVersionTransition(v1alpha1.TransitionCompleted, "", nil)
+				Ω(p.Status.VersionHistory[0].Outcome).Should(Equal(v1alpha1.TransitionFailed))
+			})
+		})
+
+		Context("finish without an open transition", func() {
+			It("should be a no-op on an empty history", func() {
+				p.Status.FinishVersionTransition(v1alpha1.TransitionCompleted, "", nil)
+				Ω(p.Status.VersionHistory).Should(BeEmpty())
+			})
+		})
+
+		Context("a long history", func() {
+			BeforeEach(func() {
+				for i := 0; i < 15; i++ {
+					from := fmt.Sprintf("0.%d.0", i)
+					to := fmt.Sprintf("0.%d.0", i+1)
+					p.Status.StartVersionTransition(from, to)
+					p.Status.FinishVersionTransition(v1alpha1.TransitionCompleted, "", nil)
+				}
+			})
+
+			It("should keep only the most recent entries", func() {
+				Ω(p.Status.VersionHistory).Should(HaveLen(10))
+				Ω(p.Status.VersionHistory[0].FromVersion).Should(Equal("0.5.0"))
+				Ω(p.Status.VersionHistory[9].ToVersion).Should(Equal("0.15.0"))
+			})
+		})
+	})
 })
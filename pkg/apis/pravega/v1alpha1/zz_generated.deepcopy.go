@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -25,10 +26,78 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminGatewaySpec) DeepCopyInto(out *AdminGatewaySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminGatewaySpec.
+func (in *AdminGatewaySpec) DeepCopy() *AdminGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogSpec) DeepCopyInto(out *AuditLogSpec) {
+	*out = *in
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogSpec.
+func (in *AuditLogSpec) DeepCopy() *AuditLogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthenticationSpec) DeepCopyInto(out *AuthenticationSpec) {
+	*out = *in
+	if in.DelegationTokenTTLSeconds != nil {
+		in, out := &in.DelegationTokenTTLSeconds, &out.DelegationTokenTTLSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TokenRefreshThresholdSeconds != nil {
+		in, out := &in.TokenRefreshThresholdSeconds, &out.TokenRefreshThresholdSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Keycloak != nil {
+		in, out := &in.Keycloak, &out.Keycloak
+		*out = new(KeycloakSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthenticationSpec.
+func (in *AuthenticationSpec) DeepCopy() *AuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BookkeeperImageSpec) DeepCopyInto(out *BookkeeperImageSpec) {
 	*out = *in
-	out.ImageSpec = in.ImageSpec
+	in.ImageSpec.DeepCopyInto(&out.ImageSpec)
 	return
 }
 
@@ -48,7 +117,7 @@ func (in *BookkeeperSpec) DeepCopyInto(out *BookkeeperSpec) {
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
 		*out = new(BookkeeperImageSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Storage != nil {
 		in, out := &in.Storage, &out.Storage
@@ -72,6 +141,18 @@ func (in *BookkeeperSpec) DeepCopyInto(out *BookkeeperSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -116,6 +197,60 @@ func (in *BookkeeperStorageSpec) DeepCopy() *BookkeeperStorageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BookkeeperTLS) DeepCopyInto(out *BookkeeperTLS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BookkeeperTLS.
+func (in *BookkeeperTLS) DeepCopy() *BookkeeperTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(BookkeeperTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpgradeSpec) DeepCopyInto(out *CanaryUpgradeSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpgradeSpec.
+func (in *CanaryUpgradeSpec) DeepCopy() *CanaryUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerTLS) DeepCopyInto(out *CertManagerTLS) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+	if in.ExtraDnsNames != nil {
+		in, out := &in.ExtraDnsNames, &out.ExtraDnsNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerTLS.
+func (in *CertManagerTLS) DeepCopy() *CertManagerTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
 	*out = *in
@@ -135,10 +270,60 @@ func (in *ClusterCondition) DeepCopy() *ClusterCondition {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	*out = *in
+	if in.Zookeeper != nil {
+		in, out := &in.Zookeeper, &out.Zookeeper
+		*out = new(ZookeeperSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ExternalAccess != nil {
 		in, out := &in.ExternalAccess, &out.ExternalAccess
 		*out = new(ExternalAccess)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMesh != nil {
+		in, out := &in.ServiceMesh, &out.ServiceMesh
+		*out = new(ServiceMeshSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(AuthenticationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretsProvider != nil {
+		in, out := &in.SecretsProvider, &out.SecretsProvider
+		*out = new(SecretsProviderSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityPolicy != nil {
+		in, out := &in.SecurityPolicy, &out.SecurityPolicy
+		*out = new(SecurityPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependencyWait != nil {
+		in, out := &in.DependencyWait, &out.DependencyWait
+		*out = new(DependencyWaitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeSnapshot != nil {
+		in, out := &in.UpgradeSnapshot, &out.UpgradeSnapshot
+		*out = new(UpgradeSnapshotSpec)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Bookkeeper != nil {
 		in, out := &in.Bookkeeper, &out.Bookkeeper
@@ -172,6 +357,23 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		copy(*out, *in)
 	}
 	in.Members.DeepCopyInto(&out.Members)
+	if in.OptionRewrites != nil {
+		in, out := &in.OptionRewrites, &out.OptionRewrites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VersionHistory != nil {
+		in, out := &in.VersionHistory, &out.VersionHistory
+		*out = make([]VersionTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingMaintenance != nil {
+		in, out := &in.PendingMaintenance, &out.PendingMaintenance
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -185,6 +387,43 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DebugSpec) DeepCopyInto(out *DebugSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DebugSpec.
+func (in *DebugSpec) DeepCopy() *DebugSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DebugSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyWaitSpec) DeepCopyInto(out *DependencyWaitSpec) {
+	*out = *in
+	if in.ExtraEndpoints != nil {
+		in, out := &in.ExtraEndpoints, &out.ExtraEndpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyWaitSpec.
+func (in *DependencyWaitSpec) DeepCopy() *DependencyWaitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyWaitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ECSSpec) DeepCopyInto(out *ECSSpec) {
 	*out = *in
@@ -204,6 +443,43 @@ func (in *ECSSpec) DeepCopy() *ECSSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalAccess) DeepCopyInto(out *ExternalAccess) {
 	*out = *in
+	if in.ControllerEnabled != nil {
+		in, out := &in.ControllerEnabled, &out.ControllerEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SegmentStoreEnabled != nil {
+		in, out := &in.SegmentStoreEnabled, &out.SegmentStoreEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SegmentStoreLoadBalancerIPs != nil {
+		in, out := &in.SegmentStoreLoadBalancerIPs, &out.SegmentStoreLoadBalancerIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = new(RoutesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -217,6 +493,22 @@ func (in *ExternalAccess) DeepCopy() *ExternalAccess {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraConfigSpec) DeepCopyInto(out *ExtraConfigSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraConfigSpec.
+func (in *ExtraConfigSpec) DeepCopy() *ExtraConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FileSystemSpec) DeepCopyInto(out *FileSystemSpec) {
 	*out = *in
@@ -257,6 +549,11 @@ func (in *HDFSSpec) DeepCopy() *HDFSSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
 	*out = *in
+	if in.PullSecrets != nil {
+		in, out := &in.PullSecrets, &out.PullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -271,153 +568,864 @@ func (in *ImageSpec) DeepCopy() *ImageSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MembersStatus) DeepCopyInto(out *MembersStatus) {
+func (in *InfluxDBSpec) DeepCopyInto(out *InfluxDBSpec) {
 	*out = *in
-	if in.Ready != nil {
-		in, out := &in.Ready, &out.Ready
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Unready != nil {
-		in, out := &in.Unready, &out.Unready
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MembersStatus.
-func (in *MembersStatus) DeepCopy() *MembersStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfluxDBSpec.
+func (in *InfluxDBSpec) DeepCopy() *InfluxDBSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MembersStatus)
+	out := new(InfluxDBSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PravegaCluster) DeepCopyInto(out *PravegaCluster) {
+func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PravegaCluster.
-func (in *PravegaCluster) DeepCopy() *PravegaCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSpec.
+func (in *IngressSpec) DeepCopy() *IngressSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PravegaCluster)
+	out := new(IngressSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PravegaCluster) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PravegaClusterList) DeepCopyInto(out *PravegaClusterList) {
+func (in *IssuerRef) DeepCopyInto(out *IssuerRef) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]PravegaCluster, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PravegaClusterList.
-func (in *PravegaClusterList) DeepCopy() *PravegaClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerRef.
+func (in *IssuerRef) DeepCopy() *IssuerRef {
 	if in == nil {
 		return nil
 	}
-	out := new(PravegaClusterList)
+	out := new(IssuerRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PravegaClusterList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioSpec) DeepCopyInto(out *IstioSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioSpec.
+func (in *IstioSpec) DeepCopy() *IstioSpec {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(IstioSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PravegaImageSpec) DeepCopyInto(out *PravegaImageSpec) {
+func (in *KeycloakSpec) DeepCopyInto(out *KeycloakSpec) {
 	*out = *in
-	out.ImageSpec = in.ImageSpec
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PravegaImageSpec.
-func (in *PravegaImageSpec) DeepCopy() *PravegaImageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakSpec.
+func (in *KeycloakSpec) DeepCopy() *KeycloakSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PravegaImageSpec)
+	out := new(KeycloakSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PravegaSpec) DeepCopyInto(out *PravegaSpec) {
+func (in *MaintenanceWindowSpec) DeepCopyInto(out *MaintenanceWindowSpec) {
 	*out = *in
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(PravegaImageSpec)
-		**out = **in
+	if in.DaysOfWeek != nil {
+		in, out := &in.DaysOfWeek, &out.DaysOfWeek
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowSpec.
+func (in *MaintenanceWindowSpec) DeepCopy() *MaintenanceWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MembersStatus) DeepCopyInto(out *MembersStatus) {
+	*out = *in
+	if in.Ready != nil {
+		in, out := &in.Ready, &out.Ready
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Unready != nil {
+		in, out := &in.Unready, &out.Unready
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MembersStatus.
+func (in *MembersStatus) DeepCopy() *MembersStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MembersStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsSpec) DeepCopyInto(out *MetricsSpec) {
+	*out = *in
+	if in.InfluxDB != nil {
+		in, out := &in.InfluxDB, &out.InfluxDB
+		*out = new(InfluxDBSpec)
+		**out = **in
+	}
+	if in.StatsD != nil {
+		in, out := &in.StatsD, &out.StatsD
+		*out = new(StatsDSpec)
+		**out = **in
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsSpec.
+func (in *MetricsSpec) DeepCopy() *MetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySpec) DeepCopyInto(out *NetworkPolicySpec) {
+	*out = *in
+	if in.ClientCIDRs != nil {
+		in, out := &in.ClientCIDRs, &out.ClientCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySpec.
+func (in *NetworkPolicySpec) DeepCopy() *NetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PravegaCluster) DeepCopyInto(out *PravegaCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PravegaCluster.
+func (in *PravegaCluster) DeepCopy() *PravegaCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(PravegaCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PravegaCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PravegaClusterList) DeepCopyInto(out *PravegaClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PravegaCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PravegaClusterList.
+func (in *PravegaClusterList) DeepCopy() *PravegaClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(PravegaClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PravegaClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PravegaImageSpec) DeepCopyInto(out *PravegaImageSpec) {
+	*out = *in
+	in.ImageSpec.DeepCopyInto(&out.ImageSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PravegaImageSpec.
+func (in *PravegaImageSpec) DeepCopy() *PravegaImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PravegaImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PravegaSpec) DeepCopyInto(out *PravegaSpec) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(PravegaImageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerImage != nil {
+		in, out := &in.ControllerImage, &out.ControllerImage
+		*out = new(PravegaImageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SegmentStoreImage != nil {
+		in, out := &in.SegmentStoreImage, &out.SegmentStoreImage
+		*out = new(PravegaImageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CacheVolumeClaimTemplate != nil {
+		in, out := &in.CacheVolumeClaimTemplate, &out.CacheVolumeClaimTemplate
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tier2 != nil {
+		in, out := &in.Tier2, &out.Tier2
+		*out = new(Tier2Spec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerServiceAccountAnnotations != nil {
+		in, out := &in.ControllerServiceAccountAnnotations, &out.ControllerServiceAccountAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SegmentStoreServiceAccountAnnotations != nil {
+		in, out := &in.SegmentStoreServiceAccountAnnotations, &out.SegmentStoreServiceAccountAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ControllerResources != nil {
+		in, out := &in.ControllerResources, &out.ControllerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SegmentStoreResources != nil {
+		in, out := &in.SegmentStoreResources, &out.SegmentStoreResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerTolerations != nil {
+		in, out := &in.ControllerTolerations, &out.ControllerTolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SegmentStoreTolerations != nil {
+		in, out := &in.SegmentStoreTolerations, &out.SegmentStoreTolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControllerAffinity != nil {
+		in, out := &in.ControllerAffinity, &out.ControllerAffinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SegmentStoreAffinity != nil {
+		in, out := &in.SegmentStoreAffinity, &out.SegmentStoreAffinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerPodLabels != nil {
+		in, out := &in.ControllerPodLabels, &out.ControllerPodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ControllerPodAnnotations != nil {
+		in, out := &in.ControllerPodAnnotations, &out.ControllerPodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SegmentStorePodLabels != nil {
+		in, out := &in.SegmentStorePodLabels, &out.SegmentStorePodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SegmentStorePodAnnotations != nil {
+		in, out := &in.SegmentStorePodAnnotations, &out.SegmentStorePodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ControllerServiceAnnotations != nil {
+		in, out := &in.ControllerServiceAnnotations, &out.ControllerServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SegmentStoreServiceAnnotations != nil {
+		in, out := &in.SegmentStoreServiceAnnotations, &out.SegmentStoreServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ControllerSessionAffinityTimeoutSeconds != nil {
+		in, out := &in.ControllerSessionAffinityTimeoutSeconds, &out.ControllerSessionAffinityTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ControllerRestService != nil {
+		in, out := &in.ControllerRestService, &out.ControllerRestService
+		*out = new(ServiceOverrideSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerGrpcService != nil {
+		in, out := &in.ControllerGrpcService, &out.ControllerGrpcService
+		*out = new(ServiceOverrideSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SegmentStoreExternalServiceAnnotations != nil {
+		in, out := &in.SegmentStoreExternalServiceAnnotations, &out.SegmentStoreExternalServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ControllerPodSecurityContext != nil {
+		in, out := &in.ControllerPodSecurityContext, &out.ControllerPodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerSecurityContext != nil {
+		in, out := &in.ControllerSecurityContext, &out.ControllerSecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SegmentStorePodSecurityContext != nil {
+		in, out := &in.SegmentStorePodSecurityContext, &out.SegmentStorePodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SegmentStoreSecurityContext != nil {
+		in, out := &in.SegmentStoreSecurityContext, &out.SegmentStoreSecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerInitContainers != nil {
+		in, out := &in.ControllerInitContainers, &out.ControllerInitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SegmentStoreInitContainers != nil {
+		in, out := &in.SegmentStoreInitContainers, &out.SegmentStoreInitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControllerSidecars != nil {
+		in, out := &in.ControllerSidecars, &out.ControllerSidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SegmentStoreSidecars != nil {
+		in, out := &in.SegmentStoreSidecars, &out.SegmentStoreSidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControllerTerminationGracePeriodSeconds != nil {
+		in, out := &in.ControllerTerminationGracePeriodSeconds, &out.ControllerTerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SegmentStoreTerminationGracePeriodSeconds != nil {
+		in, out := &in.SegmentStoreTerminationGracePeriodSeconds, &out.SegmentStoreTerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ControllerDNSConfig != nil {
+		in, out := &in.ControllerDNSConfig, &out.ControllerDNSConfig
+		*out = new(v1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SegmentStoreDNSConfig != nil {
+		in, out := &in.SegmentStoreDNSConfig, &out.SegmentStoreDNSConfig
+		*out = new(v1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]v1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControllerRuntimeClassName != nil {
+		in, out := &in.ControllerRuntimeClassName, &out.ControllerRuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.SegmentStoreRuntimeClassName != nil {
+		in, out := &in.SegmentStoreRuntimeClassName, &out.SegmentStoreRuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ControllerReadinessProbe != nil {
+		in, out := &in.ControllerReadinessProbe, &out.ControllerReadinessProbe
+		*out = new(ProbeSpec)
+		**out = **in
+	}
+	if in.ControllerLivenessProbe != nil {
+		in, out := &in.ControllerLivenessProbe, &out.ControllerLivenessProbe
+		*out = new(ProbeSpec)
+		**out = **in
+	}
+	if in.SegmentStoreReadinessProbe != nil {
+		in, out := &in.SegmentStoreReadinessProbe, &out.SegmentStoreReadinessProbe
+		*out = new(ProbeSpec)
+		**out = **in
+	}
+	if in.SegmentStoreLivenessProbe != nil {
+		in, out := &in.SegmentStoreLivenessProbe, &out.SegmentStoreLivenessProbe
+		*out = new(ProbeSpec)
+		**out = **in
+	}
+	if in.ControllerVolumes != nil {
+		in, out := &in.ControllerVolumes, &out.ControllerVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControllerVolumeMounts != nil {
+		in, out := &in.ControllerVolumeMounts, &out.ControllerVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SegmentStoreVolumes != nil {
+		in, out := &in.SegmentStoreVolumes, &out.SegmentStoreVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SegmentStoreVolumeMounts != nil {
+		in, out := &in.SegmentStoreVolumeMounts, &out.SegmentStoreVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControllerPodTemplateOverride != nil {
+		in, out := &in.ControllerPodTemplateOverride, &out.ControllerPodTemplateOverride
+		*out = new(v1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SegmentStorePodTemplateOverride != nil {
+		in, out := &in.SegmentStorePodTemplateOverride, &out.SegmentStorePodTemplateOverride
+		*out = new(v1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerJvmOptions != nil {
+		in, out := &in.ControllerJvmOptions, &out.ControllerJvmOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SegmentStoreJvmOptions != nil {
+		in, out := &in.SegmentStoreJvmOptions, &out.SegmentStoreJvmOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControllerAuditLog != nil {
+		in, out := &in.ControllerAuditLog, &out.ControllerAuditLog
+		*out = new(AuditLogSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerOptions != nil {
+		in, out := &in.ControllerOptions, &out.ControllerOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SegmentStoreOptions != nil {
+		in, out := &in.SegmentStoreOptions, &out.SegmentStoreOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdminGateway != nil {
+		in, out := &in.AdminGateway, &out.AdminGateway
+		*out = new(AdminGatewaySpec)
+		**out = **in
+	}
+	if in.ServiceAccountTokens != nil {
+		in, out := &in.ServiceAccountTokens, &out.ServiceAccountTokens
+		*out = make([]ServiceAccountTokenSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Options != nil {
-		in, out := &in.Options, &out.Options
+	if in.ExtraConfig != nil {
+		in, out := &in.ExtraConfig, &out.ExtraConfig
+		*out = make([]ExtraConfigSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SegmentStoreCanaryUpgrade != nil {
+		in, out := &in.SegmentStoreCanaryUpgrade, &out.SegmentStoreCanaryUpgrade
+		*out = new(CanaryUpgradeSpec)
+		**out = **in
+	}
+	if in.ControllerDebug != nil {
+		in, out := &in.ControllerDebug, &out.ControllerDebug
+		*out = new(DebugSpec)
+		**out = **in
+	}
+	if in.SegmentStoreDebug != nil {
+		in, out := &in.SegmentStoreDebug, &out.SegmentStoreDebug
+		*out = new(DebugSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PravegaSpec.
+func (in *PravegaSpec) DeepCopy() *PravegaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PravegaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeSpec) DeepCopyInto(out *ProbeSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeSpec.
+func (in *ProbeSpec) DeepCopy() *ProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusSpec) DeepCopyInto(out *PrometheusSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusSpec.
+func (in *PrometheusSpec) DeepCopy() *PrometheusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoutesSpec) DeepCopyInto(out *RoutesSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.CacheVolumeClaimTemplate != nil {
-		in, out := &in.CacheVolumeClaimTemplate, &out.CacheVolumeClaimTemplate
-		*out = new(v1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutesSpec.
+func (in *RoutesSpec) DeepCopy() *RoutesSpec {
+	if in == nil {
+		return nil
 	}
-	if in.Tier2 != nil {
-		in, out := &in.Tier2, &out.Tier2
-		*out = new(Tier2Spec)
-		(*in).DeepCopyInto(*out)
+	out := new(RoutesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretsProviderSpec) DeepCopyInto(out *SecretsProviderSpec) {
+	*out = *in
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultSpec)
+		**out = **in
 	}
-	if in.ControllerResources != nil {
-		in, out := &in.ControllerResources, &out.ControllerResources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsProviderSpec.
+func (in *SecretsProviderSpec) DeepCopy() *SecretsProviderSpec {
+	if in == nil {
+		return nil
 	}
-	if in.SegmentStoreResources != nil {
-		in, out := &in.SegmentStoreResources, &out.SegmentStoreResources
-		*out = new(v1.ResourceRequirements)
+	out := new(SecretsProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityPolicySpec) DeepCopyInto(out *SecurityPolicySpec) {
+	*out = *in
+	if in.FsGroup != nil {
+		in, out := &in.FsGroup, &out.FsGroup
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityPolicySpec.
+func (in *SecurityPolicySpec) DeepCopy() *SecurityPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountTokenSpec) DeepCopyInto(out *ServiceAccountTokenSpec) {
+	*out = *in
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountTokenSpec.
+func (in *ServiceAccountTokenSpec) DeepCopy() *ServiceAccountTokenSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountTokenSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshSpec) DeepCopyInto(out *ServiceMeshSpec) {
+	*out = *in
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(IstioSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshSpec.
+func (in *ServiceMeshSpec) DeepCopy() *ServiceMeshSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceOverrideSpec) DeepCopyInto(out *ServiceOverrideSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOverrideSpec.
+func (in *ServiceOverrideSpec) DeepCopy() *ServiceOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticTLS) DeepCopyInto(out *StaticTLS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticTLS.
+func (in *StaticTLS) DeepCopy() *StaticTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatsDSpec) DeepCopyInto(out *StatsDSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatsDSpec.
+func (in *StatsDSpec) DeepCopy() *StatsDSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StatsDSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSPolicy) DeepCopyInto(out *TLSPolicy) {
+	*out = *in
+	if in.Static != nil {
+		in, out := &in.Static, &out.Static
+		*out = new(StaticTLS)
+		**out = **in
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerTLS)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CipherSuites != nil {
+		in, out := &in.CipherSuites, &out.CipherSuites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bookkeeper != nil {
+		in, out := &in.Bookkeeper, &out.Bookkeeper
+		*out = new(BookkeeperTLS)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PravegaSpec.
-func (in *PravegaSpec) DeepCopy() *PravegaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSPolicy.
+func (in *TLSPolicy) DeepCopy() *TLSPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(PravegaSpec)
+	out := new(TLSPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -452,3 +1460,103 @@ func (in *Tier2Spec) DeepCopy() *Tier2Spec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeSnapshotSpec) DeepCopyInto(out *UpgradeSnapshotSpec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeSnapshotSpec.
+func (in *UpgradeSnapshotSpec) DeepCopy() *UpgradeSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSpec) DeepCopyInto(out *VaultSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSpec.
+func (in *VaultSpec) DeepCopy() *VaultSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionTransition) DeepCopyInto(out *VersionTransition) {
+	*out = *in
+	if in.FailedPods != nil {
+		in, out := &in.FailedPods, &out.FailedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionTransition.
+func (in *VersionTransition) DeepCopy() *VersionTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZookeeperAuthSpec) DeepCopyInto(out *ZookeeperAuthSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZookeeperAuthSpec.
+func (in *ZookeeperAuthSpec) DeepCopy() *ZookeeperAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ZookeeperAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZookeeperSpec) DeepCopyInto(out *ZookeeperSpec) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ZookeeperAuthSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZookeeperSpec.
+func (in *ZookeeperSpec) DeepCopy() *ZookeeperSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ZookeeperSpec)
+	in.DeepCopyInto(out)
+	return out
+}
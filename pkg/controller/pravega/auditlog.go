@@ -0,0 +1,130 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	"fmt"
+
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The controller loggers that make up the audit trail: stream lifecycle
+// operations and auth decisions
+var auditLoggers = []string{
+	"io.pravega.controller.task.Stream",
+	"io.pravega.controller.server.rpc.auth",
+}
+
+// MakeControllerAuditLogbackConfigMap generates the logback configuration
+// that routes the audit loggers to a dedicated appender. The regular
+// console appender is reproduced so enabling auditing does not change the
+// main log
+func MakeControllerAuditLogbackConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.AuditLogbackConfigMapNameForController(p.Name),
+			Namespace: p.Namespace,
+			Labels:    util.LabelsForController(p),
+		},
+		Data: map[string]string{
+			"logback.xml": auditLogbackXML(p),
+		},
+	}
+}
+
+func auditLogbackXML(p *api.PravegaCluster) string {
+	audit := p.Spec.Pravega.ControllerAuditLog
+
+	pattern := `{"time":"%d{yyyy-MM-dd'T'HH:mm:ss.SSS'Z',UTC}","level":"%level","logger":"%logger","message":"%message"}%n`
+	if audit.Format == "text" {
+		pattern = "%d{ISO8601} %-5level [%logger] %msg%n"
+	}
+
+	appender := fmt.Sprintf(`<appender name="AUDIT" class="ch.qos.logback.core.ConsoleAppender">
+    <encoder>
+      <pattern>%s</pattern>
+    </encoder>
+  </appender>`, pattern)
+	if audit.Volume != nil {
+		appender = fmt.Sprintf(`<appender name="AUDIT" class="ch.qos.logback.core.rolling.RollingFileAppender">
+    <file>%[1]s/audit.log</file>
+    <rollingPolicy class="ch.qos.logback.core.rolling.TimeBasedRollingPolicy">
+      <fileNamePattern>%[1]s/audit.%%d{yyyy-MM-dd}.log</fileNamePattern>
+      <maxHistory>7</maxHistory>
+    </rollingPolicy>
+    <encoder>
+      <pattern>%[2]s</pattern>
+    </encoder>
+  </appender>`, auditLogMountDir, pattern)
+	}
+
+	loggers := ""
+	for _, name := range auditLoggers {
+		// additivity off: audit records go only to the audit appender,
+		// so the SIEM feed stays free of duplicates
+		loggers += fmt.Sprintf(`  <logger name="%s" level="INFO" additivity="false">
+    <appender-ref ref="AUDIT"/>
+  </logger>
+`, name)
+	}
+
+	rootLevel := "INFO"
+	if p.Spec.Pravega.DebugLogging {
+		rootLevel = "DEBUG"
+	}
+	if p.Spec.Pravega.ControllerLogLevel != "" {
+		rootLevel = p.Spec.Pravega.ControllerLogLevel
+	}
+
+	return fmt.Sprintf(`<configuration>
+  <appender name="STDOUT" class="ch.qos.logback.core.ConsoleAppender">
+    <encoder>
+      <pattern>%%d{ISO8601} %%-5level [%%thread] %%logger{36}: %%msg%%n</pattern>
+    </encoder>
+  </appender>
+  %s
+%s  <root level="%s">
+    <appender-ref ref="STDOUT"/>
+  </root>
+</configuration>
+`, appender, loggers, rootLevel)
+}
+
+// configureControllerAuditLog mounts the generated audit logback
+// configuration and, when the audit trail goes to files, the volume
+// backing them. A user-supplied logback config map cannot be combined
+// with auditing; the webhook rejects that spec
+func configureControllerAuditLog(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if !p.Spec.Pravega.ControllerAuditLog.IsEnabled() {
+		return
+	}
+
+	configureLogbackConfig(podSpec, util.AuditLogbackConfigMapNameForController(p.Name))
+
+	if p.Spec.Pravega.ControllerAuditLog.Volume != nil {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         auditLogVolumeName,
+			VolumeSource: *p.Spec.Pravega.ControllerAuditLog.Volume,
+		})
+
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      auditLogVolumeName,
+			MountPath: auditLogMountDir,
+		})
+	}
+}
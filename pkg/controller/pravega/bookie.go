@@ -112,6 +112,7 @@ func makeBookiePodSpec(p *v1alpha1.PravegaCluster) *corev1.PodSpec {
 						},
 					},
 				},
+				Env: zkAuthEnvs(p),
 				VolumeMounts: []corev1.VolumeMount{
 					{
 						Name:      LedgerDiskName,
@@ -154,13 +155,25 @@ func makeBookiePodSpec(p *v1alpha1.PravegaCluster) *corev1.PodSpec {
 				},
 			},
 		},
-		Affinity: util.PodAntiAffinity("bookie", p.Name),
+		Affinity:         util.PodAntiAffinity("bookie", p.Name),
+		Tolerations:      p.Spec.Bookkeeper.Tolerations,
+		ImagePullSecrets: p.Spec.Bookkeeper.Image.PullSecrets,
+	}
+
+	if p.Spec.Bookkeeper.Affinity != nil {
+		podSpec.Affinity = p.Spec.Bookkeeper.Affinity
 	}
 
 	if p.Spec.Bookkeeper.ServiceAccountName != "" {
 		podSpec.ServiceAccountName = p.Spec.Bookkeeper.ServiceAccountName
 	}
 
+	mountBookieTLSSecret(podSpec, p)
+
+	configureZookeeperAuth(podSpec, p)
+
+	configurePodSecurityContext(podSpec, p)
+
 	return podSpec
 }
 
@@ -250,6 +263,23 @@ func MakeBookieConfigMap(pravegaCluster *v1alpha1.PravegaCluster) *corev1.Config
 		configData["BK_lostBookieRecoveryDelay"] = "60"
 	}
 
+	if pravegaCluster.Spec.TLS.IsBookkeeperTLSEnabled() {
+		configData["BK_tlsProvider"] = "OpenSSL"
+		configData["BK_tlsKeyStoreType"] = "PEM"
+		configData["BK_tlsKeyStore"] = bookieTLSMountDir + "/tls.key"
+		configData["BK_tlsCertificatePath"] = bookieTLSMountDir + "/tls.crt"
+		configData["BK_tlsTrustStoreType"] = "PEM"
+		configData["BK_tlsTrustStore"] = bookieTLSMountDir + "/ca.crt"
+		if pravegaCluster.Spec.TLS.Bookkeeper.ClientAuthentication {
+			configData["BK_tlsClientAuthentication"] = "true"
+		}
+	}
+
+	if pravegaCluster.Spec.Zookeeper.IsAuthEnabled() {
+		// The BookKeeper entrypoint appends these to the JVM command line
+		configData["BOOKIE_EXTRA_OPTS"] = strings.Join(zkAuthJavaOpts(pravegaCluster), " ")
+	}
+
 	for k, v := range pravegaCluster.Spec.Bookkeeper.Options {
 		prefixKey := fmt.Sprintf("BK_%s", k)
 		configData[prefixKey] = v
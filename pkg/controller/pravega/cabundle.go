@@ -0,0 +1,171 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	"fmt"
+
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// configureCaBundle mounts the shared CA bundle and generates a JKS
+// truststore from it in an init container, so every component and its
+// embedded clients trust the same CA chain regardless of which secret
+// issued their own certificate
+func configureCaBundle(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if !p.Spec.TLS.IsCaBundleEnabled() {
+		return
+	}
+
+	vol := corev1.Volume{
+		Name: caBundleVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: p.Spec.TLS.CaBundleSecret,
+			},
+		},
+	}
+	truststoreVol := corev1.Volume{
+		Name: truststoreVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	podSpec.Volumes = append(podSpec.Volumes, vol, truststoreVol)
+
+	// keytool refuses multi-cert PEM files, so the bundle is split into
+	// one file per certificate before importing
+	script := fmt.Sprintf(
+		"set -e; "+
+			"cd %s; "+
+			"rm -f %s ca-*.pem; "+
+			"awk 'BEGIN{n=0} /BEGIN CERTIFICATE/{n++} {print > (\"ca-\" n \".pem\")}' %s/%s; "+
+			"for f in ca-*.pem; do "+
+			"keytool -importcert -noprompt -alias \"${f%%.pem}\" -file \"$f\" -keystore %s -storepass \"$TRUSTSTORE_PASSWORD\"; "+
+			"done; "+
+			"rm -f ca-*.pem",
+		truststoreMountDir, truststoreFile, caBundleMountDir, caBundleKey, truststoreFile)
+
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:            "truststore-gen",
+		Image:           util.PravegaImage(p),
+		ImagePullPolicy: util.PravegaControllerImagePullPolicy(p),
+		Command:         []string{"sh", "-c", script},
+		Env:             truststorePasswordEnv(p),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      caBundleVolumeName,
+				MountPath: caBundleMountDir,
+				ReadOnly:  true,
+			},
+			{
+				Name:      truststoreVolumeName,
+				MountPath: truststoreMountDir,
+			},
+		},
+	})
+
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts,
+		corev1.VolumeMount{
+			Name:      caBundleVolumeName,
+			MountPath: caBundleMountDir,
+			ReadOnly:  true,
+		},
+		corev1.VolumeMount{
+			Name:      truststoreVolumeName,
+			MountPath: truststoreMountDir,
+			ReadOnly:  true,
+		},
+	)
+}
+
+// truststorePasswordEnv returns the password protecting the generated
+// truststore, taken from the configured secret when there is one
+func truststorePasswordEnv(p *api.PravegaCluster) []corev1.EnvVar {
+	if p.Spec.TLS != nil && p.Spec.TLS.TruststorePasswordSecret != "" {
+		return []corev1.EnvVar{
+			{
+				Name: "TRUSTSTORE_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: p.Spec.TLS.TruststorePasswordSecret,
+						},
+						Key: "password",
+					},
+				},
+			},
+		}
+	}
+	return []corev1.EnvVar{
+		{
+			Name:  "TRUSTSTORE_PASSWORD",
+			Value: defaultTruststorePassword,
+		},
+	}
+}
+
+// tlsPasswordEnvs injects the keystore and truststore passwords from their
+// secrets. The JVM options reference them as $KEYSTORE_PASSWORD and
+// $TRUSTSTORE_PASSWORD, which the sh entrypoint expands, so the passwords
+// stay out of the config maps
+func tlsPasswordEnvs(p *api.PravegaCluster) []corev1.EnvVar {
+	if p.Spec.TLS == nil {
+		return nil
+	}
+
+	var envs []corev1.EnvVar
+	if p.Spec.TLS.KeystorePasswordSecret != "" {
+		envs = append(envs, corev1.EnvVar{
+			Name: "KEYSTORE_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: p.Spec.TLS.KeystorePasswordSecret,
+					},
+					Key: "password",
+				},
+			},
+		})
+	}
+	if p.Spec.TLS.TruststorePasswordSecret != "" {
+		envs = append(envs, truststorePasswordEnv(p)...)
+	}
+	return envs
+}
+
+// caBundleJavaOpts points the JVM default truststore at the generated JKS,
+// so internal clients pick up the CA chain without per-client configuration
+func caBundleJavaOpts(p *api.PravegaCluster) []string {
+	if !p.Spec.TLS.IsCaBundleEnabled() {
+		return nil
+	}
+
+	password := defaultTruststorePassword
+	if p.Spec.TLS.TruststorePasswordSecret != "" {
+		password = "$TRUSTSTORE_PASSWORD"
+	}
+	return []string{
+		fmt.Sprintf("-Djavax.net.ssl.trustStore=%s/%s", truststoreMountDir, truststoreFile),
+		fmt.Sprintf("-Djavax.net.ssl.trustStorePassword=%s", password),
+	}
+}
+
+// tlsPasswordJavaOpts returns the keystore password option when the
+// password comes from a secret
+func tlsPasswordJavaOpts(p *api.PravegaCluster) []string {
+	if p.Spec.TLS == nil || p.Spec.TLS.KeystorePasswordSecret == "" {
+		return nil
+	}
+	return []string{"-Djavax.net.ssl.keyStorePassword=$KEYSTORE_PASSWORD"}
+}
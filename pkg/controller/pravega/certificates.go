@@ -0,0 +1,83 @@
+/**
+ * Copyright (c) 2018 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	"fmt"
+
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// certificateGVK identifies the cert-manager Certificate kind. The objects
+// are built as unstructured so the operator does not have to link against a
+// particular cert-manager release
+var certificateGVK = schema.GroupVersionKind{
+	Group:   "certmanager.k8s.io",
+	Version: "v1alpha1",
+	Kind:    "Certificate",
+}
+
+// MakeControllerCertificate returns a Certificate covering the controller
+// client service
+func MakeControllerCertificate(p *api.PravegaCluster) *unstructured.Unstructured {
+	dnsNames := serviceDNSNames(util.ServiceNameForController(p.Name), p.Namespace)
+	return makeCertificate(p, p.Spec.TLS.Static.ControllerSecret, dnsNames)
+}
+
+// MakeSegmentStoreCertificate returns a Certificate covering every segment
+// store pod through a wildcard on the headless service domain
+func MakeSegmentStoreCertificate(p *api.PravegaCluster) *unstructured.Unstructured {
+	headlessSvc := util.HeadlessServiceNameForSegmentStore(p.Name)
+	dnsNames := append(serviceDNSNames(headlessSvc, p.Namespace),
+		fmt.Sprintf("*.%s.%s.svc.cluster.local", headlessSvc, p.Namespace))
+	return makeCertificate(p, p.Spec.TLS.Static.SegmentStoreSecret, dnsNames)
+}
+
+func serviceDNSNames(serviceName string, namespace string) []string {
+	return []string{
+		serviceName,
+		fmt.Sprintf("%s.%s", serviceName, namespace),
+		fmt.Sprintf("%s.%s.svc", serviceName, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, namespace),
+	}
+}
+
+func makeCertificate(p *api.PravegaCluster, secretName string, dnsNames []string) *unstructured.Unstructured {
+	dnsNames = append(dnsNames, p.Spec.TLS.CertManager.ExtraDnsNames...)
+	names := make([]interface{}, len(dnsNames))
+	for i, name := range dnsNames {
+		names[i] = name
+	}
+
+	cert := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"secretName": secretName,
+				"commonName": dnsNames[0],
+				"dnsNames":   names,
+				"issuerRef": map[string]interface{}{
+					"name": p.Spec.TLS.CertManager.IssuerRef.Name,
+					"kind": p.Spec.TLS.CertManager.IssuerRef.Kind,
+				},
+			},
+		},
+	}
+	cert.SetGroupVersionKind(certificateGVK)
+	// The Certificate is named after the secret it produces
+	cert.SetName(secretName)
+	cert.SetNamespace(p.Namespace)
+	cert.SetLabels(util.LabelsForPravegaCluster(p))
+	return cert
+}
@@ -18,4 +18,63 @@ const (
 	segmentStoreKind      = "pravega-segmentstore"
 	tlsVolumeName         = "tls-secret"
 	tlsMountDir           = "/etc/secret-volume"
+	tlsRestVolumeName     = "rest-tls-secret"
+	tlsRestMountDir       = "/etc/secret-volume/rest"
+	tlsGrpcVolumeName     = "grpc-tls-secret"
+	tlsGrpcMountDir       = "/etc/secret-volume/grpc"
+	bookieTLSVolumeName   = "bookie-tls"
+	bookieTLSMountDir     = "/etc/bookkeeper/tls"
+	scratchTmpVolumeName  = "scratch-tmp"
+	scratchTmpMountPoint  = "/tmp"
+	scratchLogVolumeName  = "scratch-log"
+	scratchLogMountPoint  = "/opt/pravega/logs"
+
+	externalDNSAnnotationKey = "external-dns.alpha.kubernetes.io/hostname"
+
+	passwordAuthVolumeName = "password-auth"
+	passwordAuthMountDir   = "/etc/pravega/auth"
+
+	authPluginVolumeName = "auth-plugin"
+	authPluginMountDir   = "/opt/pravega/pluginlib"
+	authPluginImageDir   = "/plugins"
+
+	logbackVolumeName = "logback-config"
+	logbackMountDir   = "/etc/pravega/logback"
+
+	auditLogVolumeName = "audit-log"
+	auditLogMountDir   = "/opt/pravega/auditlogs"
+
+	keycloakVolumeName = "keycloak-config"
+	keycloakMountDir   = "/etc/pravega/keycloak"
+	keycloakConfigKey  = "keycloak.json"
+
+	zkAuthVolumeName = "zk-auth"
+	zkAuthMountDir   = "/etc/pravega/zk-auth"
+	zkJaasConfigKey  = "jaas.conf"
+
+	snapshotVolumeName = "zk-snapshot"
+	snapshotMountDir   = "/snapshot"
+
+	caBundleVolumeName   = "ca-bundle"
+	caBundleMountDir     = "/etc/pravega/ca-bundle"
+	caBundleKey          = "ca.crt"
+	truststoreVolumeName = "truststore"
+	truststoreMountDir   = "/etc/pravega/truststore"
+	truststoreFile       = "truststore.jks"
+
+	// defaultTruststorePassword protects the generated JKS truststore.
+	// JKS requires one, but the truststore only holds public CA certs
+	defaultTruststorePassword = "changeit"
+
+	defaultWaitImage          = "busybox:1.31"
+	defaultWaitTimeoutSeconds = int32(300)
+
+	adminVolumeName  = "admin-credentials"
+	adminMountDir    = "/etc/pravega/admin"
+	defaultAdminPort = int32(9999)
+
+	// ConfigHashAnnotation carries a digest of the generated config map
+	// data on the pod templates, so a configuration change rolls the pods
+	// through the regular Deployment/StatefulSet update machinery
+	ConfigHashAnnotation = "pravega.config.hash"
 )
@@ -0,0 +1,115 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	"fmt"
+
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// debugJavaOpts returns the JVM flags enabling the configured debug
+// listeners.
+func debugJavaOpts(debug *api.DebugSpec) []string {
+	if debug == nil {
+		return nil
+	}
+
+	var opts []string
+	if debug.JmxPort != 0 {
+		opts = append(opts,
+			"-Dcom.sun.management.jmxremote",
+			fmt.Sprintf("-Dcom.sun.management.jmxremote.port=%d", debug.JmxPort),
+			fmt.Sprintf("-Dcom.sun.management.jmxremote.rmi.port=%d", debug.JmxPort),
+			"-Dcom.sun.management.jmxremote.authenticate=false",
+			"-Dcom.sun.management.jmxremote.ssl=false",
+		)
+	}
+	if debug.JdwpPort != 0 {
+		opts = append(opts,
+			fmt.Sprintf("-agentlib:jdwp=transport=dt_socket,server=y,suspend=n,address=%d", debug.JdwpPort))
+	}
+	return opts
+}
+
+// debugContainerPorts returns the container ports of the configured debug
+// listeners.
+func debugContainerPorts(debug *api.DebugSpec) []corev1.ContainerPort {
+	if debug == nil {
+		return nil
+	}
+
+	var ports []corev1.ContainerPort
+	if debug.JmxPort != 0 {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          "jmx",
+			ContainerPort: debug.JmxPort,
+		})
+	}
+	if debug.JdwpPort != 0 {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          "jdwp",
+			ContainerPort: debug.JdwpPort,
+		})
+	}
+	return ports
+}
+
+// makeDebugService builds the in-cluster service exposing the debug ports
+// of a component. Debug ports are deliberately kept off the external
+// services.
+func makeDebugService(p *api.PravegaCluster, name string, labels map[string]string, debug *api.DebugSpec) *corev1.Service {
+	var ports []corev1.ServicePort
+	if debug.JmxPort != 0 {
+		ports = append(ports, corev1.ServicePort{
+			Name: "jmx",
+			Port: debug.JmxPort,
+		})
+	}
+	if debug.JdwpPort != 0 {
+		ports = append(ports, corev1.ServicePort{
+			Name: "jdwp",
+			Port: debug.JdwpPort,
+		})
+	}
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: p.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Ports:    ports,
+			Selector: labels,
+		},
+	}
+}
+
+// MakeControllerDebugService builds the in-cluster service exposing the
+// controller debug ports.
+func MakeControllerDebugService(p *api.PravegaCluster) *corev1.Service {
+	return makeDebugService(p, util.DebugServiceNameForController(p.Name), util.LabelsForController(p), p.Spec.Pravega.ControllerDebug)
+}
+
+// MakeSegmentStoreDebugService builds the in-cluster service exposing the
+// segment store debug ports.
+func MakeSegmentStoreDebugService(p *api.PravegaCluster) *corev1.Service {
+	return makeDebugService(p, util.DebugServiceNameForSegmentStore(p.Name), util.LabelsForSegmentStore(p), p.Spec.Pravega.SegmentStoreDebug)
+}
@@ -0,0 +1,53 @@
+/**
+ * Copyright (c) 2018 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+)
+
+// Port names carry a protocol prefix when the Istio compatibility mode is
+// on, so the mesh applies the right protocol handling to each service port.
+
+func controllerRestPortName(p *api.PravegaCluster) string {
+	if p.Spec.IsIstioEnabled() {
+		return "http-rest"
+	}
+	return "rest"
+}
+
+func controllerGrpcPortName(p *api.PravegaCluster) string {
+	if p.Spec.IsIstioEnabled() {
+		return "grpc-client"
+	}
+	return "grpc"
+}
+
+func segmentStorePortName(p *api.PravegaCluster) string {
+	if p.Spec.IsIstioEnabled() {
+		return "tcp-server"
+	}
+	return "server"
+}
+
+// istioPodAnnotations returns sidecar injection annotations for Pravega
+// pods, or nil when the Istio compatibility mode is off. The application
+// containers are held until the proxy is ready and HTTP probes are rewritten
+// so kubelet checks keep working under strict mesh mTLS.
+func istioPodAnnotations(p *api.PravegaCluster) map[string]string {
+	if !p.Spec.IsIstioEnabled() {
+		return nil
+	}
+	return map[string]string{
+		"proxy.istio.io/config":                  `{ "holdApplicationUntilProxyStarts": true }`,
+		"sidecar.istio.io/rewriteAppHTTPProbers": "true",
+	}
+}
@@ -0,0 +1,147 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	"fmt"
+
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// metricsJavaOpts returns the -D options enabling the configured metrics
+// reporters. Credentials are referenced through env vars expanded by the
+// entrypoint, so they do not end up in the config maps.
+func metricsJavaOpts(p *api.PravegaCluster) []string {
+	if p.Spec.Pravega.Metrics == nil {
+		return nil
+	}
+
+	var opts []string
+	if influxdb := p.Spec.Pravega.Metrics.InfluxDB; influxdb != nil {
+		opts = append(opts,
+			"-Dmetrics.enableStatistics=true",
+			"-Dmetrics.enableInfluxDBReporter=true",
+			fmt.Sprintf("-Dmetrics.influxDBURI=%s", influxdb.Uri),
+		)
+		if influxdb.Database != "" {
+			opts = append(opts, fmt.Sprintf("-Dmetrics.influxDBName=%s", influxdb.Database))
+		}
+		if influxdb.CredentialsSecret != "" {
+			opts = append(opts,
+				"-Dmetrics.influxDBUserName=$INFLUXDB_USERNAME",
+				"-Dmetrics.influxDBPassword=$INFLUXDB_PASSWORD",
+			)
+		}
+	}
+	if statsd := p.Spec.Pravega.Metrics.StatsD; statsd != nil {
+		port := statsd.Port
+		if port == 0 {
+			port = 8125
+		}
+		opts = append(opts,
+			"-Dmetrics.enableStatistics=true",
+			"-Dmetrics.enableStatsdReporter=true",
+			fmt.Sprintf("-Dmetrics.statsDHost=%s", statsd.Host),
+			fmt.Sprintf("-Dmetrics.statsDPort=%d", port),
+		)
+	}
+	if p.Spec.Pravega.Metrics.Prometheus != nil {
+		opts = append(opts,
+			"-Dmetrics.enableStatistics=true",
+			"-Dmetrics.enablePrometheusReporter=true",
+			fmt.Sprintf("-Dmetrics.prometheusPort=%d", prometheusPort(p)),
+		)
+	}
+	return opts
+}
+
+// prometheusEnabled returns whether the Prometheus reporter is configured
+func prometheusEnabled(p *api.PravegaCluster) bool {
+	return p.Spec.Pravega.Metrics != nil && p.Spec.Pravega.Metrics.Prometheus != nil
+}
+
+// prometheusPort returns the configured metrics port, falling back to the
+// standard 9091
+func prometheusPort(p *api.PravegaCluster) int32 {
+	if prometheusEnabled(p) && p.Spec.Pravega.Metrics.Prometheus.Port != 0 {
+		return p.Spec.Pravega.Metrics.Prometheus.Port
+	}
+	return 9091
+}
+
+// metricsContainerPorts returns the metrics port opened on the Pravega
+// containers when the Prometheus reporter is on.
+func metricsContainerPorts(p *api.PravegaCluster) []corev1.ContainerPort {
+	if !prometheusEnabled(p) {
+		return nil
+	}
+	return []corev1.ContainerPort{
+		{
+			Name:          "metrics",
+			ContainerPort: prometheusPort(p),
+		},
+	}
+}
+
+// metricsServicePorts returns the metrics port exposed on the component
+// services when the Prometheus reporter is on.
+func metricsServicePorts(p *api.PravegaCluster) []corev1.ServicePort {
+	if !prometheusEnabled(p) {
+		return nil
+	}
+	return []corev1.ServicePort{
+		{
+			Name: "metrics",
+			Port: prometheusPort(p),
+		},
+	}
+}
+
+// metricsPodAnnotations returns the scrape annotations added to the pods
+// when the Prometheus reporter is on.
+func metricsPodAnnotations(p *api.PravegaCluster) map[string]string {
+	if !prometheusEnabled(p) {
+		return nil
+	}
+	return map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   fmt.Sprintf("%d", prometheusPort(p)),
+	}
+}
+
+// metricsEnvs returns the env vars carrying the metrics sink credentials.
+func metricsEnvs(p *api.PravegaCluster) []corev1.EnvVar {
+	if p.Spec.Pravega.Metrics == nil {
+		return nil
+	}
+
+	var envs []corev1.EnvVar
+	if influxdb := p.Spec.Pravega.Metrics.InfluxDB; influxdb != nil && influxdb.CredentialsSecret != "" {
+		for _, entry := range []struct{ env, key string }{
+			{"INFLUXDB_USERNAME", "username"},
+			{"INFLUXDB_PASSWORD", "password"},
+		} {
+			envs = append(envs, corev1.EnvVar{
+				Name: entry.env,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: influxdb.CredentialsSecret,
+						},
+						Key: entry.key,
+					},
+				},
+			})
+		}
+	}
+	return envs
+}
@@ -0,0 +1,111 @@
+/**
+ * Copyright (c) 2018 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// MakeNetworkPolicies builds NetworkPolicies that restrict ingress to the
+// cluster components to intra-cluster traffic plus the configured client
+// CIDRs. Egress is left unrestricted so components can reach Zookeeper and
+// tier 2 storage, which the operator does not manage.
+func MakeNetworkPolicies(p *api.PravegaCluster) []*networkingv1.NetworkPolicy {
+	clusterPeer := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: util.LabelsForPravegaCluster(p),
+		},
+	}
+
+	clientPeers := []networkingv1.NetworkPolicyPeer{clusterPeer}
+	for _, cidr := range p.Spec.NetworkPolicy.ClientCIDRs {
+		clientPeers = append(clientPeers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{
+				CIDR: cidr,
+			},
+		})
+	}
+
+	makePolicy := func(name string, podLabels map[string]string, ports []int, peers []networkingv1.NetworkPolicyPeer) *networkingv1.NetworkPolicy {
+		var policyPorts []networkingv1.NetworkPolicyPort
+		protocolTCP := corev1.ProtocolTCP
+		for _, port := range ports {
+			p := intstr.FromInt(port)
+			policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{
+				Protocol: &protocolTCP,
+				Port:     &p,
+			})
+		}
+		return &networkingv1.NetworkPolicy{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "NetworkPolicy",
+				APIVersion: "networking.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: p.Namespace,
+				Labels:    util.LabelsForPravegaCluster(p),
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{
+					MatchLabels: podLabels,
+				},
+				PolicyTypes: []networkingv1.PolicyType{
+					networkingv1.PolicyTypeIngress,
+				},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{
+						From:  peers,
+						Ports: policyPorts,
+					},
+				},
+			},
+		}
+	}
+
+	controllerPorts := []int{int(util.ControllerGrpcPort(p)), int(util.ControllerRestPort(p))}
+	segmentStorePorts := []int{12345}
+
+	// The Prometheus reporter annotates the pods for scraping; the
+	// policies must admit the scrapes as well
+	if prometheusEnabled(p) {
+		controllerPorts = append(controllerPorts, int(prometheusPort(p)))
+		segmentStorePorts = append(segmentStorePorts, int(prometheusPort(p)))
+	}
+
+	// Same for the debug listeners: their services select the component
+	// pods, so the connections land on ports the policies must cover
+	for _, port := range debugContainerPorts(p.Spec.Pravega.ControllerDebug) {
+		controllerPorts = append(controllerPorts, int(port.ContainerPort))
+	}
+	for _, port := range debugContainerPorts(p.Spec.Pravega.SegmentStoreDebug) {
+		segmentStorePorts = append(segmentStorePorts, int(port.ContainerPort))
+	}
+
+	if p.Spec.Pravega.AdminGateway != nil {
+		segmentStorePorts = append(segmentStorePorts, int(adminGatewayPort(p)))
+	}
+
+	return []*networkingv1.NetworkPolicy{
+		makePolicy(util.DeploymentNameForController(p.Name),
+			util.LabelsForController(p), controllerPorts, clientPeers),
+		makePolicy(util.StatefulSetNameForSegmentstore(p.Name),
+			util.LabelsForSegmentStore(p), segmentStorePorts, clientPeers),
+		makePolicy(util.StatefulSetNameForBookie(p.Name),
+			util.LabelsForBookie(p), []int{3181},
+			[]networkingv1.NetworkPolicyPeer{clusterPeer}),
+	}
+}
@@ -11,16 +11,21 @@
 package pravega
 
 import (
+	"regexp"
+	"sort"
 	"strings"
 
 	"fmt"
 
 	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
 	"github.com/pravega/pravega-operator/pkg/util"
+	log "github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -41,18 +46,40 @@ func MakeControllerDeployment(p *api.PravegaCluster) *appsv1.Deployment {
 			Selector: &metav1.LabelSelector{
 				MatchLabels: util.LabelsForController(p),
 			},
+			// Surge one pod at a time and never drop below the declared
+			// replicas, so version changes roll the controllers one by one
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxSurge:       &intstr.IntOrString{IntVal: 1},
+					MaxUnavailable: &intstr.IntOrString{IntVal: 0},
+				},
+			},
 		},
 	}
 }
 
 func MakeControllerPodTemplate(p *api.PravegaCluster) corev1.PodTemplateSpec {
-	return corev1.PodTemplateSpec{
+	template := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels:      util.LabelsForController(p),
-			Annotations: map[string]string{"pravega.version": p.Spec.Version},
+			Labels: util.MergeMaps(p.Spec.Pravega.ControllerPodLabels, util.LabelsForController(p)),
+			Annotations: util.MergeMaps(p.Spec.Pravega.ControllerPodAnnotations, istioPodAnnotations(p), metricsPodAnnotations(p), vaultPodAnnotations(p),
+				securityProfileAnnotations("pravega-controller", p.Spec.Pravega.ControllerSeccompProfile, p.Spec.Pravega.ControllerApparmorProfile), map[string]string{
+					"pravega.version": p.Spec.Version,
+					// Changing the config map data changes this hash and rolls the deployment
+					ConfigHashAnnotation: util.ConfigMapHash(MakeControllerConfigMap(p).Data),
+				}),
 		},
 		Spec: *makeControllerPodSpec(p),
 	}
+
+	if err := util.ApplyPodTemplateOverride(&template, p.Spec.Pravega.ControllerPodTemplateOverride); err != nil {
+		// The webhook rejects overrides that fail to merge; should one slip
+		// through anyway, it must not stop the cluster from being deployed
+		log.Printf("failed to apply controller pod template override: %v", err)
+	}
+
+	return template
 }
 
 func makeControllerPodSpec(p *api.PravegaCluster) *corev1.PodSpec {
@@ -60,19 +87,19 @@ func makeControllerPodSpec(p *api.PravegaCluster) *corev1.PodSpec {
 		Containers: []corev1.Container{
 			{
 				Name:            "pravega-controller",
-				Image:           util.PravegaImage(p),
-				ImagePullPolicy: p.Spec.Pravega.Image.PullPolicy,
+				Image:           util.PravegaControllerImage(p),
+				ImagePullPolicy: util.PravegaControllerImagePullPolicy(p),
 				Args: []string{
 					"controller",
 				},
 				Ports: []corev1.ContainerPort{
 					{
 						Name:          "rest",
-						ContainerPort: 10080,
+						ContainerPort: util.ControllerRestPort(p),
 					},
 					{
 						Name:          "grpc",
-						ContainerPort: 9090,
+						ContainerPort: util.ControllerGrpcPort(p),
 					},
 				},
 				EnvFrom: []corev1.EnvFromSource{
@@ -83,12 +110,22 @@ func makeControllerPodSpec(p *api.PravegaCluster) *corev1.PodSpec {
 							},
 						},
 					},
+					{
+						// Sensitive values live in a secret, not in the config map
+						SecretRef: &corev1.SecretEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: controllerSecretName(p),
+							},
+						},
+					},
 				},
-				Resources: *p.Spec.Pravega.ControllerResources,
+				Env: append(append(append(append(tokenSigningKeyEnv(p), tlsPasswordEnvs(p)...), zkAuthEnvs(p)...), metricsEnvs(p)...),
+					secretOptionEnvs(util.MergeMaps(p.Spec.Pravega.Options, p.Spec.Pravega.ControllerOptions))...),
+				Resources: controllerResources(p),
 				ReadinessProbe: &corev1.Probe{
 					Handler: corev1.Handler{
 						Exec: &corev1.ExecAction{
-							Command: util.HealthcheckCommand(9090),
+							Command: util.HealthcheckCommand(util.ControllerGrpcPort(p)),
 						},
 					},
 					// Controller pods start fast. We give it up to 1 minute to become ready.
@@ -98,7 +135,7 @@ func makeControllerPodSpec(p *api.PravegaCluster) *corev1.PodSpec {
 				LivenessProbe: &corev1.Probe{
 					Handler: corev1.Handler{
 						Exec: &corev1.ExecAction{
-							Command: util.HealthcheckCommand(9090),
+							Command: util.HealthcheckCommand(util.ControllerGrpcPort(p)),
 						},
 					},
 					// We start the liveness probe from the maximum time the pod can take
@@ -111,33 +148,519 @@ func makeControllerPodSpec(p *api.PravegaCluster) *corev1.PodSpec {
 				},
 			},
 		},
-		Affinity: util.PodAntiAffinity("pravega-controller", p.Name),
+		InitContainers:   p.Spec.Pravega.ControllerInitContainers,
+		Affinity:         util.PodAntiAffinity("pravega-controller", p.Name),
+		Tolerations:      p.Spec.Pravega.ControllerTolerations,
+		ImagePullSecrets: p.Spec.Pravega.Image.PullSecrets,
+		HostAliases:      p.Spec.Pravega.HostAliases,
+	}
+
+	podSpec.Containers[0].Ports = append(podSpec.Containers[0].Ports, metricsContainerPorts(p)...)
+	podSpec.Containers[0].Ports = append(podSpec.Containers[0].Ports, debugContainerPorts(p.Spec.Pravega.ControllerDebug)...)
+
+	podSpec.Containers = append(podSpec.Containers, p.Spec.Pravega.ControllerSidecars...)
+
+	if p.Spec.Pravega.ControllerTerminationGracePeriodSeconds != nil {
+		podSpec.TerminationGracePeriodSeconds = p.Spec.Pravega.ControllerTerminationGracePeriodSeconds
+	}
+
+	if p.Spec.Pravega.ControllerDNSPolicy != "" {
+		podSpec.DNSPolicy = p.Spec.Pravega.ControllerDNSPolicy
+	}
+
+	if p.Spec.Pravega.ControllerDNSConfig != nil {
+		podSpec.DNSConfig = p.Spec.Pravega.ControllerDNSConfig
+	}
+
+	if p.Spec.Pravega.ControllerRuntimeClassName != nil {
+		podSpec.RuntimeClassName = p.Spec.Pravega.ControllerRuntimeClassName
+	}
+
+	if p.Spec.Pravega.ControllerAffinity != nil {
+		podSpec.Affinity = p.Spec.Pravega.ControllerAffinity
 	}
 
+	util.ApplyProbeOverrides(podSpec.Containers[0].ReadinessProbe, p.Spec.Pravega.ControllerReadinessProbe)
+	util.ApplyProbeOverrides(podSpec.Containers[0].LivenessProbe, p.Spec.Pravega.ControllerLivenessProbe)
+
 	if p.Spec.Pravega.ControllerServiceAccountName != "" {
 		podSpec.ServiceAccountName = p.Spec.Pravega.ControllerServiceAccountName
 	}
 
+	if p.Spec.Pravega.ControllerPriorityClassName != "" {
+		podSpec.PriorityClassName = p.Spec.Pravega.ControllerPriorityClassName
+	}
+
+	if p.Spec.Pravega.ControllerPodSecurityContext != nil {
+		podSpec.SecurityContext = p.Spec.Pravega.ControllerPodSecurityContext
+	}
+
+	if p.Spec.Pravega.ControllerSecurityContext != nil {
+		podSpec.Containers[0].SecurityContext = p.Spec.Pravega.ControllerSecurityContext
+	}
+
+	configurePodSecurityContext(podSpec, p)
+
 	configureControllerTLSSecrets(podSpec, p)
 
+	configureCaBundle(podSpec, p)
+
+	configurePasswordAuthSecret(podSpec, p)
+
+	configureAuthHandlerPlugin(podSpec, p)
+
+	configureKeycloakAuth(podSpec, p)
+
+	configureZookeeperAuth(podSpec, p)
+
+	configureDependencyWait(podSpec, p, []string{p.Spec.ZookeeperUri})
+
+	configureLogbackConfig(podSpec, p.Spec.Pravega.ControllerLogbackConfigMap)
+
+	configureControllerAuditLog(podSpec, p)
+
+	configureExtraConfig(podSpec, p)
+
+	configureReadOnlyRootFilesystem(podSpec, p)
+
+	configureServiceAccountTokens(podSpec, p)
+
+	podSpec.Volumes = append(podSpec.Volumes, p.Spec.Pravega.ControllerVolumes...)
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, p.Spec.Pravega.ControllerVolumeMounts...)
+
 	return podSpec
 }
 
+// controllerResources guards against a nil resources pointer, which can occur
+// when a pod template is built before the spec defaults have been persisted.
+// Requests and limits may include cpu, memory and ephemeral-storage.
+func controllerResources(p *api.PravegaCluster) corev1.ResourceRequirements {
+	if p.Spec.Pravega.ControllerResources == nil {
+		return corev1.ResourceRequirements{}
+	}
+	return *p.Spec.Pravega.ControllerResources
+}
+
 func configureControllerTLSSecrets(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
-	if p.Spec.TLS.IsSecureController() {
-		vol := corev1.Volume{
-			Name: tlsVolumeName,
+	if p.Spec.IsMeshMTLSEnabled() {
+		// The mesh encrypts pod-to-pod traffic; Pravega's own TLS stays off
+		return
+	}
+	if !p.Spec.TLS.IsSecureController() {
+		return
+	}
+
+	mountSecret := func(volumeName string, secretName string, mountDir string) {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: volumeName,
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: p.Spec.TLS.Static.ControllerSecret,
+					SecretName: secretName,
 				},
 			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: mountDir,
+		})
+	}
+
+	if p.Spec.TLS.Static.ControllerSecret != "" {
+		mountSecret(tlsVolumeName, p.Spec.TLS.Static.ControllerSecret, tlsMountDir)
+	}
+	// The REST and gRPC listeners may use their own TLS material, e.g. when
+	// REST is terminated at an Ingress while gRPC keeps end-to-end TLS
+	if p.Spec.TLS.Static.ControllerRestSecret != "" {
+		mountSecret(tlsRestVolumeName, p.Spec.TLS.Static.ControllerRestSecret, tlsRestMountDir)
+	}
+	if p.Spec.TLS.Static.ControllerGrpcSecret != "" {
+		mountSecret(tlsGrpcVolumeName, p.Spec.TLS.Static.ControllerGrpcSecret, tlsGrpcMountDir)
+	}
+}
+
+// configureReadOnlyRootFilesystem makes the Pravega container of the pod run
+// with a read-only root filesystem, mounting emptyDir volumes over the
+// scratch directories the process still needs to write to.
+func configureReadOnlyRootFilesystem(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if !p.Spec.Pravega.ReadOnlyRootFilesystem {
+		return
+	}
+
+	boolTrue := true
+	if podSpec.Containers[0].SecurityContext == nil {
+		podSpec.Containers[0].SecurityContext = &corev1.SecurityContext{}
+	}
+	podSpec.Containers[0].SecurityContext.ReadOnlyRootFilesystem = &boolTrue
+
+	podSpec.Volumes = append(podSpec.Volumes,
+		corev1.Volume{
+			Name: scratchTmpVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		corev1.Volume{
+			Name: scratchLogVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	)
+
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts,
+		corev1.VolumeMount{
+			Name:      scratchTmpVolumeName,
+			MountPath: scratchTmpMountPoint,
+		},
+		corev1.VolumeMount{
+			Name:      scratchLogVolumeName,
+			MountPath: scratchLogMountPoint,
+		},
+	)
+}
+
+// configurePasswordAuthSecret mounts the user-provided password file on the
+// controller so PasswordAuthHandler can validate credentials against it.
+func configurePasswordAuthSecret(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if p.Spec.Authentication == nil || p.Spec.Authentication.PasswordAuthSecret == "" {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: passwordAuthVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: p.Spec.Authentication.PasswordAuthSecret,
+			},
+		},
+	})
+
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      passwordAuthVolumeName,
+		MountPath: passwordAuthMountDir,
+		ReadOnly:  true,
+	})
+}
+
+// configureAuthHandlerPlugin places custom auth-handler JARs into the
+// controller's plugin directory, either copied out of a plugin image by an
+// init container or mounted straight from a secret.
+func configureAuthHandlerPlugin(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if p.Spec.Authentication == nil {
+		return
+	}
+
+	if p.Spec.Authentication.PluginSecret != "" {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: authPluginVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: p.Spec.Authentication.PluginSecret,
+				},
+			},
+		})
+	} else if p.Spec.Authentication.PluginImage != "" {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: authPluginVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+
+		podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+			Name:  "auth-plugin",
+			Image: p.Spec.Authentication.PluginImage,
+			Command: []string{
+				"/bin/sh", "-c",
+				fmt.Sprintf("cp -r %s/. %s", authPluginImageDir, authPluginMountDir),
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      authPluginVolumeName,
+					MountPath: authPluginMountDir,
+				},
+			},
+		})
+	} else {
+		return
+	}
+
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      authPluginVolumeName,
+		MountPath: authPluginMountDir,
+		ReadOnly:  true,
+	})
+}
+
+// configureKeycloakAuth mounts the Keycloak service account JSON on the
+// component so the Keycloak auth handler can authenticate against the
+// configured realm. The handler JARs themselves come in through the
+// regular plugin mechanisms.
+func configureKeycloakAuth(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if !p.Spec.Authentication.IsKeycloakEnabled() {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: keycloakVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: p.Spec.Authentication.Keycloak.CredentialsSecret,
+			},
+		},
+	})
+
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      keycloakVolumeName,
+		MountPath: keycloakMountDir,
+		ReadOnly:  true,
+	})
+}
+
+// keycloakConfigData returns the env entries pointing the Keycloak auth
+// handler at its realm, client and mounted service account file
+func keycloakConfigData(p *api.PravegaCluster) map[string]string {
+	if !p.Spec.Authentication.IsKeycloakEnabled() {
+		return nil
+	}
+	return map[string]string{
+		"KEYCLOAK_REALM":                p.Spec.Authentication.Keycloak.Realm,
+		"KEYCLOAK_CLIENT_ID":            p.Spec.Authentication.Keycloak.ClientId,
+		"KEYCLOAK_SERVICE_ACCOUNT_FILE": keycloakMountDir + "/" + keycloakConfigKey,
+	}
+}
+
+// configureLogbackConfig mounts a user-provided logback configuration on
+// the Pravega container. The JVM is pointed at it through the
+// -Dlogback.configurationFile option emitted by the config map builders.
+func configureLogbackConfig(podSpec *corev1.PodSpec, configMapName string) {
+	if configMapName == "" {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: logbackVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMapName,
+				},
+			},
+		},
+	})
+
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      logbackVolumeName,
+		MountPath: logbackMountDir,
+		ReadOnly:  true,
+	})
+}
+
+// logbackOpt points the JVM at the mounted logback configuration
+func logbackOpt() string {
+	return "-Dlogback.configurationFile=" + logbackMountDir + "/logback.xml"
+}
+
+// gcJavaOpts returns the GC flags of the selected profile. ZGC is only
+// available on the JDK 11 images bundled from Pravega 0.5.0 onwards; on
+// older versions the selection falls back to G1.
+func gcJavaOpts(p *api.PravegaCluster) []string {
+	g1 := []string{"-XX:+UseG1GC", "-XX:MaxGCPauseMillis=200"}
+
+	switch p.Spec.Pravega.GcProfile {
+	case "", "default":
+		return nil
+	case "g1":
+		return g1
+	case "zgc":
+		if match, _ := util.CompareVersions(p.Spec.Version, "0.5.0", ">="); !match {
+			log.Printf("ZGC is not available on Pravega %s, falling back to G1", p.Spec.Version)
+			return g1
 		}
-		podSpec.Volumes = append(podSpec.Volumes, vol)
+		return []string{"-XX:+UnlockExperimentalVMOptions", "-XX:+UseZGC"}
+	default:
+		log.Printf("unknown GC profile %q, using the JVM default collector", p.Spec.Pravega.GcProfile)
+		return nil
+	}
+}
+
+// memorySizingOpts derives -Xmx and -XX:MaxDirectMemorySize from the
+// declared container memory limit. Explicit sizing replaces
+// UseCGroupMemoryLimitForHeap, which newer JDKs removed and which leaves
+// direct memory unbounded.
+func memorySizingOpts(resources *corev1.ResourceRequirements, heapPercent int32, directPercent int32) []string {
+	if resources == nil {
+		return nil
+	}
+	limit, ok := resources.Limits[corev1.ResourceMemory]
+	if !ok || limit.Value() <= 0 {
+		return nil
+	}
+	limitMb := limit.Value() / (1024 * 1024)
+	return []string{
+		fmt.Sprintf("-Xmx%dm", limitMb*int64(heapPercent)/100),
+		fmt.Sprintf("-XX:MaxDirectMemorySize=%dm", limitMb*int64(directPercent)/100),
+	}
+}
+
+func percentOrDefault(percent int32, def int32) int32 {
+	if percent != 0 {
+		return percent
+	}
+	return def
+}
+
+// configureDependencyWait replaces the entrypoint's WAIT_FOR loop with an
+// init container probing the given endpoints, so a pod stuck on a missing
+// dependency fails with a clear init-container event instead of
+// crash-looping.
+func configureDependencyWait(podSpec *corev1.PodSpec, p *api.PravegaCluster, endpoints []string) {
+	if !p.Spec.DependencyWait.IsEnabled() {
+		return
+	}
+
+	endpoints = append(endpoints, p.Spec.DependencyWait.ExtraEndpoints...)
+	script := fmt.Sprintf(
+		"deadline=$(($(date +%%s)+%d)); for ep in %s; do until nc -z -w 2 ${ep%%%%:*} ${ep##*:}; do if [ $(date +%%s) -ge $deadline ]; then echo \"timed out waiting for $ep\"; exit 1; fi; sleep 2; done; done",
+		dependencyWaitTimeout(p), strings.Join(endpoints, " "))
+
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:    "wait-for-dependencies",
+		Image:   dependencyWaitImage(p),
+		Command: []string{"/bin/sh", "-c", script},
+	})
+}
+
+func dependencyWaitTimeout(p *api.PravegaCluster) int32 {
+	if p.Spec.DependencyWait.TimeoutSeconds != 0 {
+		return p.Spec.DependencyWait.TimeoutSeconds
+	}
+	return defaultWaitTimeoutSeconds
+}
+
+func dependencyWaitImage(p *api.PravegaCluster) string {
+	if p.Spec.DependencyWait.Image != "" {
+		return p.Spec.DependencyWait.Image
+	}
+	return defaultWaitImage
+}
+
+// secretKeyRefRegexp matches option values of the form
+// "secretKeyRef(name,key)"
+var secretKeyRefRegexp = regexp.MustCompile(`^secretKeyRef\(([^,]+),([^)]+)\)$`)
+
+// parseSecretKeyRef resolves an option value of the form
+// "secretKeyRef(name,key)" into the referenced secret name and key.
+func parseSecretKeyRef(value string) (secretName string, key string, ok bool) {
+	m := secretKeyRefRegexp.FindStringSubmatch(value)
+	if m == nil {
+		return "", "", false
+	}
+	return strings.TrimSpace(m[1]), strings.TrimSpace(m[2]), true
+}
+
+// secretOptionEnvName derives the env var a secret-backed option value is
+// injected through, e.g. "extendeds3.secretKey" becomes
+// "OPT_EXTENDEDS3_SECRETKEY"
+func secretOptionEnvName(option string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(option))
+	return "OPT_" + sanitized
+}
+
+// secretOptionEnvs builds the env vars backing the secretKeyRef option
+// values, so the secrets never appear in plaintext in the config map. The
+// entrypoint shell expands them when JAVA_OPTS is evaluated.
+func secretOptionEnvs(options map[string]string) []corev1.EnvVar {
+	var names []string
+	for name, value := range options {
+		if _, _, ok := parseSecretKeyRef(value); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var envs []corev1.EnvVar
+	for _, name := range names {
+		secretName, key, _ := parseSecretKeyRef(options[name])
+		envs = append(envs, corev1.EnvVar{
+			Name: secretOptionEnvName(name),
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+					Key: key,
+				},
+			},
+		})
+	}
+	return envs
+}
+
+// configureExtraConfig mounts the configured ConfigMaps and Secrets as
+// config files on the Pravega container of the pod.
+func configureExtraConfig(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	for i := range p.Spec.Pravega.ExtraConfig {
+		extra := &p.Spec.Pravega.ExtraConfig[i]
+
+		volumeSource := corev1.VolumeSource{}
+		if extra.Secret != "" {
+			volumeSource.Secret = &corev1.SecretVolumeSource{
+				SecretName: extra.Secret,
+			}
+		} else {
+			volumeSource.ConfigMap = &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: extra.ConfigMap,
+				},
+			}
+		}
+
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         extra.Name,
+			VolumeSource: volumeSource,
+		})
+
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      extra.Name,
+			MountPath: extra.MountPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// configureServiceAccountTokens mounts the configured bound service-account
+// token volumes on the Pravega container of the pod.
+func configureServiceAccountTokens(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	for i := range p.Spec.Pravega.ServiceAccountTokens {
+		token := &p.Spec.Pravega.ServiceAccountTokens[i]
+
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: token.Name,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience:          token.Audience,
+								ExpirationSeconds: token.ExpirationSeconds,
+								Path:              "token",
+							},
+						},
+					},
+				},
+			},
+		})
 
 		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
-			Name:      tlsVolumeName,
-			MountPath: tlsMountDir,
+			Name:      token.Name,
+			MountPath: token.MountPath,
+			ReadOnly:  true,
 		})
 	}
 }
@@ -151,7 +674,13 @@ func MakeControllerConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
 		"-Dpravegaservice.clusterName=" + p.Name,
 	}
 
-	if match, _ := util.CompareVersions(p.Spec.Version, "0.4.0", ">="); match {
+	memoryOpts := memorySizingOpts(p.Spec.Pravega.ControllerResources,
+		percentOrDefault(p.Spec.Pravega.ControllerHeapPercent, 50),
+		percentOrDefault(p.Spec.Pravega.ControllerDirectMemoryPercent, 25))
+
+	if match, _ := util.CompareVersions(p.Spec.Version, "0.4.0", ">="); match && len(memoryOpts) == 0 {
+		// Without a memory limit to size the heap from, fall back to the
+		// cgroup-based sizing.
 		// Pravega < 0.4 uses a Java version that does not support the options below
 		javaOpts = append(javaOpts,
 			"-XX:+UnlockExperimentalVMOptions",
@@ -160,7 +689,44 @@ func MakeControllerConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
 		)
 	}
 
-	for name, value := range p.Spec.Pravega.Options {
+	javaOpts = append(javaOpts, memoryOpts...)
+	javaOpts = append(javaOpts, gcJavaOpts(p)...)
+	javaOpts = append(javaOpts, caBundleJavaOpts(p)...)
+	javaOpts = append(javaOpts, tlsPasswordJavaOpts(p)...)
+	javaOpts = append(javaOpts, tlsHardeningJavaOpts(p)...)
+	javaOpts = append(javaOpts, zkAuthJavaOpts(p)...)
+
+	if p.Spec.Pravega.ControllerLogbackConfigMap != "" || p.Spec.Pravega.ControllerAuditLog.IsEnabled() {
+		javaOpts = append(javaOpts, logbackOpt())
+	}
+
+	if auth := p.Spec.Authentication; auth != nil {
+		if auth.DelegationTokenTTLSeconds != nil {
+			javaOpts = append(javaOpts,
+				fmt.Sprintf("-Dcontroller.auth.tokenTtlInSeconds=%d", *auth.DelegationTokenTTLSeconds))
+		}
+		if auth.TokenRefreshThresholdSeconds != nil {
+			javaOpts = append(javaOpts,
+				fmt.Sprintf("-Dcontroller.auth.tokenRefreshThresholdSeconds=%d", *auth.TokenRefreshThresholdSeconds))
+		}
+	}
+
+	javaOpts = append(javaOpts, metricsJavaOpts(p)...)
+	javaOpts = append(javaOpts, debugJavaOpts(p.Spec.Pravega.ControllerDebug)...)
+	if p.Spec.Pravega.SegmentContainerCount != 0 {
+		javaOpts = append(javaOpts,
+			fmt.Sprintf("-Dpravegaservice.containerCount=%d", p.Spec.Pravega.SegmentContainerCount))
+	}
+
+	javaOpts = append(javaOpts, p.Spec.Pravega.ControllerJvmOptions...)
+
+	for name, value := range util.MergeMaps(p.Spec.Pravega.Options, p.Spec.Pravega.ControllerOptions) {
+		if _, _, ok := parseSecretKeyRef(value); ok {
+			// Secret-backed values are injected as env vars and
+			// expanded by the entrypoint, not written to the config map
+			javaOpts = append(javaOpts, fmt.Sprintf("-D%v=$%v", name, secretOptionEnvName(name)))
+			continue
+		}
 		javaOpts = append(javaOpts, fmt.Sprintf("-D%v=%v", name, value))
 	}
 
@@ -168,15 +734,76 @@ func MakeControllerConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
 		"CLUSTER_NAME":           p.Name,
 		"ZK_URL":                 p.Spec.ZookeeperUri,
 		"JAVA_OPTS":              strings.Join(javaOpts, " "),
-		"REST_SERVER_PORT":       "10080",
-		"CONTROLLER_SERVER_PORT": "9090",
+		"REST_SERVER_PORT":       fmt.Sprintf("%d", util.ControllerRestPort(p)),
+		"CONTROLLER_SERVER_PORT": fmt.Sprintf("%d", util.ControllerGrpcPort(p)),
 		"AUTHORIZATION_ENABLED":  "false",
-		"TOKEN_SIGNING_KEY":      "secret",
 		"USER_PASSWORD_FILE":     "/etc/pravega/conf/passwd",
 		"TLS_ENABLED":            "false",
 		"WAIT_FOR":               p.Spec.ZookeeperUri,
 	}
 
+	if p.Spec.DependencyWait.IsEnabled() {
+		// The wait init container takes over the dependency check
+		delete(configData, "WAIT_FOR")
+	}
+
+	if p.Spec.Pravega.ControllerLogLevel != "" {
+		configData["log.level"] = p.Spec.Pravega.ControllerLogLevel
+	}
+
+	if p.Spec.Authentication.IsEnabled() {
+		configData["AUTHORIZATION_ENABLED"] = "true"
+	}
+
+	if p.Spec.Authentication != nil && p.Spec.Authentication.PasswordAuthSecret != "" {
+		// Mounting a password file implies enabling authorization
+		configData["AUTHORIZATION_ENABLED"] = "true"
+		configData["USER_PASSWORD_FILE"] = passwordAuthMountDir + "/passwd"
+	}
+
+	for k, v := range keycloakConfigData(p) {
+		configData[k] = v
+	}
+
+	// TLS secrets are expected to follow the kubernetes.io/tls layout:
+	// "tls.crt" and "tls.key", plus an optional "ca.crt" truststore.
+	// Under mesh mTLS the sidecars encrypt traffic and Pravega's own TLS
+	// stays off
+	if !p.Spec.IsMeshMTLSEnabled() && p.Spec.TLS != nil && p.Spec.TLS.Static != nil {
+		if p.Spec.TLS.Static.ControllerSecret != "" {
+			configData["TLS_ENABLED"] = "true"
+			configData["TLS_CERT_FILE"] = tlsMountDir + "/tls.crt"
+			configData["TLS_KEY_FILE"] = tlsMountDir + "/tls.key"
+			configData["TLS_TRUST_STORE"] = tlsMountDir + "/ca.crt"
+		}
+		// Split listener secrets take precedence over the combined one
+		if p.Spec.TLS.Static.ControllerGrpcSecret != "" {
+			configData["TLS_ENABLED"] = "true"
+			configData["TLS_CERT_FILE"] = tlsGrpcMountDir + "/tls.crt"
+			configData["TLS_KEY_FILE"] = tlsGrpcMountDir + "/tls.key"
+			configData["TLS_TRUST_STORE"] = tlsGrpcMountDir + "/ca.crt"
+		}
+		if p.Spec.TLS.Static.ControllerRestSecret != "" {
+			configData["REST_TLS_ENABLED"] = "true"
+			configData["REST_TLS_CERT_FILE"] = tlsRestMountDir + "/tls.crt"
+			configData["REST_TLS_KEY_FILE"] = tlsRestMountDir + "/tls.key"
+		}
+		// The shared CA bundle supersedes the chain shipped with the
+		// listener secret
+		if p.Spec.TLS.IsCaBundleEnabled() && configData["TLS_ENABLED"] == "true" {
+			configData["TLS_TRUST_STORE"] = caBundleMountDir + "/" + caBundleKey
+		}
+	}
+
+	// TLS material rendered by the Vault agent takes precedence over any
+	// mounted secret
+	if !p.Spec.IsMeshMTLSEnabled() && vaultTLSEnabled(p) {
+		configData["TLS_ENABLED"] = "true"
+		configData["TLS_CERT_FILE"] = vaultSecretsDir + "/tls.crt"
+		configData["TLS_KEY_FILE"] = vaultSecretsDir + "/tls.key"
+		configData["TLS_TRUST_STORE"] = vaultSecretsDir + "/ca.crt"
+	}
+
 	configMap := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ConfigMap",
@@ -193,10 +820,103 @@ func MakeControllerConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
 	return configMap
 }
 
+// tokenSigningKeyEnv injects TOKEN_SIGNING_KEY from the user-provided
+// secret. An explicit env var takes precedence over values pulled in
+// through envFrom, so this overrides the generated default key
+func tokenSigningKeyEnv(p *api.PravegaCluster) []corev1.EnvVar {
+	if p.Spec.Authentication == nil || p.Spec.Authentication.TokenSigningKeySecret == "" {
+		return nil
+	}
+	env := []corev1.EnvVar{
+		{
+			Name: "TOKEN_SIGNING_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: p.Spec.Authentication.TokenSigningKeySecret,
+					},
+					Key: "TOKEN_SIGNING_KEY",
+				},
+			},
+		},
+	}
+	// During a rotation the staged key rides along as a secondary key,
+	// so tokens signed by either key validate
+	if p.Spec.Authentication.NextTokenSigningKeySecret != "" {
+		env = append(env, corev1.EnvVar{
+			Name: "TOKEN_SIGNING_KEY_NEXT",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: p.Spec.Authentication.NextTokenSigningKeySecret,
+					},
+					Key: "TOKEN_SIGNING_KEY",
+				},
+			},
+		})
+	}
+	return env
+}
+
+func controllerSecretName(p *api.PravegaCluster) string {
+	if p.Spec.Pravega.ControllerSecretName != "" {
+		return p.Spec.Pravega.ControllerSecretName
+	}
+	return util.SecretNameForController(p.Name)
+}
+
+// MakeControllerSecret builds the generated secret holding the sensitive
+// controller configuration. It is only created when the spec does not
+// reference a pre-existing secret.
+func MakeControllerSecret(p *api.PravegaCluster) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.SecretNameForController(p.Name),
+			Namespace: p.Namespace,
+			Labels:    util.LabelsForController(p),
+		},
+		StringData: map[string]string{
+			"TOKEN_SIGNING_KEY": "secret",
+		},
+	}
+}
+
 func MakeControllerService(p *api.PravegaCluster) *corev1.Service {
 	serviceType := corev1.ServiceTypeClusterIP
-	if p.Spec.ExternalAccess.Enabled {
+	annotations := p.Spec.Pravega.ControllerServiceAnnotations
+	var sourceRanges []string
+	var trafficPolicy corev1.ServiceExternalTrafficPolicyType
+	var loadBalancerIP string
+	nodePorts := map[string]int32{}
+	if p.Spec.ExternalAccess.IsEnabledForController() {
 		serviceType = p.Spec.ExternalAccess.Type
+		annotations = util.MergeMaps(externalAccessAnnotations(p), annotations)
+		if serviceType == corev1.ServiceTypeLoadBalancer {
+			sourceRanges = p.Spec.ExternalAccess.LoadBalancerSourceRanges
+			loadBalancerIP = p.Spec.ExternalAccess.LoadBalancerIP
+		}
+		trafficPolicy = p.Spec.ExternalAccess.ExternalTrafficPolicy
+		nodePorts["rest"] = p.Spec.Pravega.ControllerRestNodePort
+		nodePorts["grpc"] = p.Spec.Pravega.ControllerGrpcNodePort
+		if p.Spec.ExternalAccess.DomainName != "" {
+			annotations = util.MergeMaps(annotations, map[string]string{
+				externalDNSAnnotationKey: util.ExternalDNSHostname(
+					util.ServiceNameForController(p.Name), p.Spec.ExternalAccess.DomainName),
+			})
+		}
+	}
+	var affinityConfig *corev1.SessionAffinityConfig
+	if p.Spec.Pravega.ControllerSessionAffinity == corev1.ServiceAffinityClientIP &&
+		p.Spec.Pravega.ControllerSessionAffinityTimeoutSeconds != nil {
+		affinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{
+				TimeoutSeconds: p.Spec.Pravega.ControllerSessionAffinityTimeoutSeconds,
+			},
+		}
 	}
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -204,27 +924,239 @@ func MakeControllerService(p *api.PravegaCluster) *corev1.Service {
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      util.ServiceNameForController(p.Name),
-			Namespace: p.Namespace,
-			Labels:    util.LabelsForController(p),
+			Name:        util.ServiceNameForController(p.Name),
+			Namespace:   p.Namespace,
+			Labels:      util.LabelsForController(p),
+			Annotations: annotations,
 		},
 		Spec: corev1.ServiceSpec{
 			Type: serviceType,
+			Ports: append([]corev1.ServicePort{
+				{
+					Name:     controllerRestPortName(p),
+					Port:     util.ControllerRestPort(p),
+					NodePort: nodePorts["rest"],
+				},
+				{
+					Name:     controllerGrpcPortName(p),
+					Port:     util.ControllerGrpcPort(p),
+					NodePort: nodePorts["grpc"],
+				},
+			}, metricsServicePorts(p)...),
+			Selector:                 util.LabelsForController(p),
+			LoadBalancerSourceRanges: sourceRanges,
+			ExternalTrafficPolicy:    trafficPolicy,
+			LoadBalancerIP:           loadBalancerIP,
+			SessionAffinity:          p.Spec.Pravega.ControllerSessionAffinity,
+			SessionAffinityConfig:    affinityConfig,
+		},
+	}
+}
+
+func MakeControllerIngress(p *api.PravegaCluster) *extensionsv1beta1.Ingress {
+	ingressSpec := p.Spec.ExternalAccess.Ingress
+	annotations := ingressSpec.Annotations
+	if ingressSpec.IngressClass != "" {
+		annotations = util.MergeMaps(map[string]string{
+			"kubernetes.io/ingress.class": ingressSpec.IngressClass,
+		}, annotations)
+	}
+
+	ingress := &extensionsv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        util.IngressNameForController(p.Name),
+			Namespace:   p.Namespace,
+			Labels:      util.LabelsForController(p),
+			Annotations: annotations,
+		},
+		Spec: extensionsv1beta1.IngressSpec{
+			Rules: []extensionsv1beta1.IngressRule{
+				{
+					Host: ingressSpec.Host,
+					IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+						HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+							Paths: []extensionsv1beta1.HTTPIngressPath{
+								{
+									Backend: extensionsv1beta1.IngressBackend{
+										ServiceName: util.ServiceNameForController(p.Name),
+										ServicePort: intstr.FromInt(int(util.ControllerRestPort(p))),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if ingressSpec.TLSSecret != "" {
+		ingress.Spec.TLS = []extensionsv1beta1.IngressTLS{
+			{
+				Hosts:      []string{ingressSpec.Host},
+				SecretName: ingressSpec.TLSSecret,
+			},
+		}
+	}
+
+	return ingress
+}
+
+// externalAccessAnnotations expands the configured load balancer profile and
+// merges the explicit external access annotations on top of it.
+func externalAccessAnnotations(p *api.PravegaCluster) map[string]string {
+	var profile map[string]string
+	switch p.Spec.ExternalAccess.Profile {
+	case "aws-nlb":
+		profile = map[string]string{
+			"service.beta.kubernetes.io/aws-load-balancer-type":                              "nlb",
+			"service.beta.kubernetes.io/aws-load-balancer-cross-zone-load-balancing-enabled": "true",
+			"service.beta.kubernetes.io/aws-load-balancer-healthcheck-interval":              "10",
+		}
+	case "gcp-internal":
+		profile = map[string]string{
+			"cloud.google.com/load-balancer-type": "Internal",
+		}
+	case "azure-internal":
+		profile = map[string]string{
+			"service.beta.kubernetes.io/azure-load-balancer-internal": "true",
+		}
+	case "":
+		// no profile selected
+	default:
+		log.Printf("ignoring unknown external access profile %q", p.Spec.ExternalAccess.Profile)
+	}
+	return util.MergeMaps(profile, p.Spec.ExternalAccess.Annotations)
+}
+
+// MakeControllerSplitServices builds separate services for the controller
+// REST and gRPC endpoints so each can use its own type and annotations.
+func MakeControllerSplitServices(p *api.PravegaCluster) []*corev1.Service {
+	makeService := func(suffix string, port corev1.ServicePort, override *api.ServiceOverrideSpec) *corev1.Service {
+		serviceType := corev1.ServiceTypeClusterIP
+		if p.Spec.ExternalAccess.IsEnabledForController() {
+			serviceType = p.Spec.ExternalAccess.Type
+		}
+		annotations := p.Spec.Pravega.ControllerServiceAnnotations
+		if override != nil {
+			if override.Type != "" {
+				serviceType = override.Type
+			}
+			annotations = util.MergeMaps(annotations, override.Annotations)
+		}
+		var sourceRanges []string
+		if serviceType == corev1.ServiceTypeLoadBalancer {
+			sourceRanges = p.Spec.ExternalAccess.LoadBalancerSourceRanges
+		}
+		return &corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("%s-%s", util.ServiceNameForController(p.Name), suffix),
+				Namespace:   p.Namespace,
+				Labels:      util.LabelsForController(p),
+				Annotations: annotations,
+			},
+			Spec: corev1.ServiceSpec{
+				Type:                     serviceType,
+				Ports:                    []corev1.ServicePort{port},
+				Selector:                 util.LabelsForController(p),
+				LoadBalancerSourceRanges: sourceRanges,
+			},
+		}
+	}
+
+	return []*corev1.Service{
+		makeService("rest", corev1.ServicePort{
+			Name:     controllerRestPortName(p),
+			Port:     util.ControllerRestPort(p),
+			NodePort: p.Spec.Pravega.ControllerRestNodePort,
+		}, p.Spec.Pravega.ControllerRestService),
+		makeService("grpc", corev1.ServicePort{
+			Name:     controllerGrpcPortName(p),
+			Port:     util.ControllerGrpcPort(p),
+			NodePort: p.Spec.Pravega.ControllerGrpcNodePort,
+		}, p.Spec.Pravega.ControllerGrpcService),
+	}
+}
+
+func MakeControllerHeadlessService(p *api.PravegaCluster) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        util.HeadlessServiceNameForController(p.Name),
+			Namespace:   p.Namespace,
+			Labels:      util.LabelsForController(p),
+			Annotations: p.Spec.Pravega.ControllerServiceAnnotations,
+		},
+		Spec: corev1.ServiceSpec{
 			Ports: []corev1.ServicePort{
 				{
-					Name: "rest",
-					Port: 10080,
+					Name: controllerRestPortName(p),
+					Port: util.ControllerRestPort(p),
 				},
 				{
-					Name: "grpc",
-					Port: 9090,
+					Name: controllerGrpcPortName(p),
+					Port: util.ControllerGrpcPort(p),
 				},
 			},
-			Selector: util.LabelsForController(p),
+			Selector:  util.LabelsForController(p),
+			ClusterIP: corev1.ClusterIPNone,
 		},
 	}
 }
 
+// MakeControllerRoutes builds OpenShift Routes for the controller REST and
+// gRPC endpoints. The Route type is not part of the vendored client
+// libraries, so the objects are built as unstructured resources.
+func MakeControllerRoutes(p *api.PravegaCluster) []*unstructured.Unstructured {
+	routesSpec := p.Spec.ExternalAccess.Routes
+
+	makeRoute := func(suffix string, host string, targetPort string, tls map[string]interface{}) *unstructured.Unstructured {
+		route := &unstructured.Unstructured{}
+		route.SetAPIVersion("route.openshift.io/v1")
+		route.SetKind("Route")
+		route.SetName(fmt.Sprintf("%s-%s", util.ServiceNameForController(p.Name), suffix))
+		route.SetNamespace(p.Namespace)
+		route.SetLabels(util.LabelsForController(p))
+		route.SetAnnotations(routesSpec.Annotations)
+
+		spec := map[string]interface{}{
+			"to": map[string]interface{}{
+				"kind": "Service",
+				"name": util.ServiceNameForController(p.Name),
+			},
+			"port": map[string]interface{}{
+				"targetPort": targetPort,
+			},
+		}
+		if host != "" {
+			spec["host"] = host
+		}
+		if tls != nil {
+			spec["tls"] = tls
+		}
+		route.Object["spec"] = spec
+		return route
+	}
+
+	return []*unstructured.Unstructured{
+		makeRoute("rest", routesSpec.RestHost, controllerRestPortName(p), nil),
+		makeRoute("grpc", routesSpec.GrpcHost, controllerGrpcPortName(p), map[string]interface{}{
+			"termination": "passthrough",
+		}),
+	}
+}
+
 func MakeControllerPodDisruptionBudget(pravegaCluster *api.PravegaCluster) *policyv1beta1.PodDisruptionBudget {
 	minAvailable := intstr.FromInt(1)
 	return &policyv1beta1.PodDisruptionBudget{
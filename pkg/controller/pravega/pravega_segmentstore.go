@@ -17,6 +17,7 @@ import (
 
 	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
 	"github.com/pravega/pravega-operator/pkg/util"
+	log "github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
@@ -52,13 +53,26 @@ func MakeSegmentStoreStatefulSet(pravegaCluster *api.PravegaCluster) *appsv1.Sta
 }
 
 func MakeSegmentStorePodTemplate(p *api.PravegaCluster) corev1.PodTemplateSpec {
-	return corev1.PodTemplateSpec{
+	template := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels:      util.LabelsForSegmentStore(p),
-			Annotations: map[string]string{"pravega.version": p.Spec.Version},
+			Labels: util.MergeMaps(p.Spec.Pravega.SegmentStorePodLabels, util.LabelsForSegmentStore(p)),
+			Annotations: util.MergeMaps(p.Spec.Pravega.SegmentStorePodAnnotations, istioPodAnnotations(p), metricsPodAnnotations(p), vaultPodAnnotations(p),
+				securityProfileAnnotations("pravega-segmentstore", p.Spec.Pravega.SegmentStoreSeccompProfile, p.Spec.Pravega.SegmentStoreApparmorProfile), map[string]string{
+					"pravega.version": p.Spec.Version,
+					// Changing the config map data changes this hash and rolls the statefulset
+					ConfigHashAnnotation: util.ConfigMapHash(MakeSegmentstoreConfigMap(p).Data),
+				}),
 		},
 		Spec: makeSegmentstorePodSpec(p),
 	}
+
+	if err := util.ApplyPodTemplateOverride(&template, p.Spec.Pravega.SegmentStorePodTemplateOverride); err != nil {
+		// The webhook rejects overrides that fail to merge; should one slip
+		// through anyway, it must not stop the cluster from being deployed
+		log.Printf("failed to apply segment store pod template override: %v", err)
+	}
+
+	return template
 }
 
 func makeSegmentstorePodSpec(p *api.PravegaCluster) corev1.PodSpec {
@@ -78,8 +92,8 @@ func makeSegmentstorePodSpec(p *api.PravegaCluster) corev1.PodSpec {
 		Containers: []corev1.Container{
 			{
 				Name:            "pravega-segmentstore",
-				Image:           util.PravegaImage(p),
-				ImagePullPolicy: p.Spec.Pravega.Image.PullPolicy,
+				Image:           util.PravegaSegmentStoreImage(p),
+				ImagePullPolicy: util.PravegaSegmentStoreImagePullPolicy(p),
 				Args: []string{
 					"segmentstore",
 				},
@@ -90,14 +104,15 @@ func makeSegmentstorePodSpec(p *api.PravegaCluster) corev1.PodSpec {
 					},
 				},
 				EnvFrom: environment,
-				Env:     util.DownwardAPIEnv(),
+				Env: append(append(append(append(append(util.DownwardAPIEnv(), tokenSigningKeyEnv(p)...), tlsPasswordEnvs(p)...), zkAuthEnvs(p)...), metricsEnvs(p)...),
+					secretOptionEnvs(util.MergeMaps(p.Spec.Pravega.Options, p.Spec.Pravega.SegmentStoreOptions))...),
 				VolumeMounts: []corev1.VolumeMount{
 					{
 						Name:      cacheVolumeName,
 						MountPath: cacheVolumeMountPoint,
 					},
 				},
-				Resources: *p.Spec.Pravega.SegmentStoreResources,
+				Resources: segmentStoreResources(p),
 				ReadinessProbe: &corev1.Probe{
 					Handler: corev1.Handler{
 						Exec: &corev1.ExecAction{
@@ -128,20 +143,119 @@ func makeSegmentstorePodSpec(p *api.PravegaCluster) corev1.PodSpec {
 				},
 			},
 		},
-		Affinity: util.PodAntiAffinity("pravega-segmentstore", p.Name),
+		InitContainers:   p.Spec.Pravega.SegmentStoreInitContainers,
+		Affinity:         util.PodAntiAffinity("pravega-segmentstore", p.Name),
+		Tolerations:      p.Spec.Pravega.SegmentStoreTolerations,
+		ImagePullSecrets: p.Spec.Pravega.Image.PullSecrets,
+		HostAliases:      p.Spec.Pravega.HostAliases,
+	}
+
+	podSpec.Containers[0].Ports = append(podSpec.Containers[0].Ports, metricsContainerPorts(p)...)
+	podSpec.Containers[0].Ports = append(podSpec.Containers[0].Ports, debugContainerPorts(p.Spec.Pravega.SegmentStoreDebug)...)
+
+	podSpec.Containers = append(podSpec.Containers, p.Spec.Pravega.SegmentStoreSidecars...)
+
+	if p.Spec.Pravega.SegmentStoreTerminationGracePeriodSeconds != nil {
+		podSpec.TerminationGracePeriodSeconds = p.Spec.Pravega.SegmentStoreTerminationGracePeriodSeconds
+	}
+
+	if p.Spec.Pravega.SegmentStoreDNSPolicy != "" {
+		podSpec.DNSPolicy = p.Spec.Pravega.SegmentStoreDNSPolicy
+	}
+
+	if p.Spec.Pravega.SegmentStoreDNSConfig != nil {
+		podSpec.DNSConfig = p.Spec.Pravega.SegmentStoreDNSConfig
+	}
+
+	if p.Spec.Pravega.SegmentStoreHostNetwork {
+		podSpec.HostNetwork = true
+		// Pods on the host network must not resolve cluster services
+		// through the node's resolver
+		if p.Spec.Pravega.SegmentStoreDNSPolicy == "" {
+			podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		}
+	}
+
+	if p.Spec.Pravega.SegmentStoreRuntimeClassName != nil {
+		podSpec.RuntimeClassName = p.Spec.Pravega.SegmentStoreRuntimeClassName
+	}
+
+	configureAdminGateway(&podSpec, p)
+
+	configureDependencyWait(&podSpec, p, append([]string{p.Spec.ZookeeperUri}, bookieWaitEndpoints(p)...))
+
+	configureLogbackConfig(&podSpec, p.Spec.Pravega.SegmentStoreLogbackConfigMap)
+
+	configureExtraConfig(&podSpec, p)
+
+	if p.Spec.Pravega.SegmentStoreDrainOnShutdown {
+		podSpec.Containers[0].Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.Handler{
+				Exec: &corev1.ExecAction{
+					Command: util.DrainCommand(12345),
+				},
+			},
+		}
+	}
+
+	if p.Spec.Pravega.SegmentStoreAffinity != nil {
+		podSpec.Affinity = p.Spec.Pravega.SegmentStoreAffinity
 	}
 
+	util.ApplyProbeOverrides(podSpec.Containers[0].ReadinessProbe, p.Spec.Pravega.SegmentStoreReadinessProbe)
+	util.ApplyProbeOverrides(podSpec.Containers[0].LivenessProbe, p.Spec.Pravega.SegmentStoreLivenessProbe)
+
 	if p.Spec.Pravega.SegmentStoreServiceAccountName != "" {
 		podSpec.ServiceAccountName = p.Spec.Pravega.SegmentStoreServiceAccountName
 	}
 
+	if p.Spec.Pravega.SegmentStorePriorityClassName != "" {
+		podSpec.PriorityClassName = p.Spec.Pravega.SegmentStorePriorityClassName
+	}
+
+	if p.Spec.Pravega.SegmentStorePodSecurityContext != nil {
+		podSpec.SecurityContext = p.Spec.Pravega.SegmentStorePodSecurityContext
+	}
+
+	if p.Spec.Pravega.SegmentStoreSecurityContext != nil {
+		podSpec.Containers[0].SecurityContext = p.Spec.Pravega.SegmentStoreSecurityContext
+	}
+
+	configurePodSecurityContext(&podSpec, p)
+
 	configureSegmentstoreTLSSecret(&podSpec, p)
 
+	mountBookieTLSSecret(&podSpec, p)
+
+	configureKeycloakAuth(&podSpec, p)
+
+	configureZookeeperAuth(&podSpec, p)
+
+	configureCaBundle(&podSpec, p)
+
 	configureTier2Filesystem(&podSpec, p.Spec.Pravega)
 
+	configureReadOnlyRootFilesystem(&podSpec, p)
+
+	configureServiceAccountTokens(&podSpec, p)
+
+	podSpec.Volumes = append(podSpec.Volumes, p.Spec.Pravega.SegmentStoreVolumes...)
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, p.Spec.Pravega.SegmentStoreVolumeMounts...)
+
 	return podSpec
 }
 
+// segmentStoreResources guards against a nil resources pointer, which can
+// occur when a pod template is built before the spec defaults have been
+// persisted. Requests and limits may include cpu, memory and
+// ephemeral-storage.
+func segmentStoreResources(p *api.PravegaCluster) corev1.ResourceRequirements {
+	if p.Spec.Pravega.SegmentStoreResources == nil {
+		return corev1.ResourceRequirements{}
+	}
+	return *p.Spec.Pravega.SegmentStoreResources
+}
+
 func MakeSegmentstoreConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
 	javaOpts := []string{
 		"-Xms1g",
@@ -151,7 +265,13 @@ func MakeSegmentstoreConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
 		"-Dpravegaservice.clusterName=" + p.Name,
 	}
 
-	if match, _ := util.CompareVersions(p.Spec.Version, "0.4.0", ">="); match {
+	memoryOpts := memorySizingOpts(p.Spec.Pravega.SegmentStoreResources,
+		percentOrDefault(p.Spec.Pravega.SegmentStoreHeapPercent, 33),
+		percentOrDefault(p.Spec.Pravega.SegmentStoreDirectMemoryPercent, 50))
+
+	if match, _ := util.CompareVersions(p.Spec.Version, "0.4.0", ">="); match && len(memoryOpts) == 0 {
+		// Without a memory limit to size the heap from, fall back to the
+		// cgroup-based sizing.
 		// Pravega < 0.4 uses a Java version that does not support the options below
 		javaOpts = append(javaOpts,
 			"-XX:+UnlockExperimentalVMOptions",
@@ -160,7 +280,51 @@ func MakeSegmentstoreConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
 		)
 	}
 
-	for name, value := range p.Spec.Pravega.Options {
+	javaOpts = append(javaOpts, memoryOpts...)
+	javaOpts = append(javaOpts, gcJavaOpts(p)...)
+	javaOpts = append(javaOpts, caBundleJavaOpts(p)...)
+	javaOpts = append(javaOpts, tlsPasswordJavaOpts(p)...)
+	javaOpts = append(javaOpts, tlsHardeningJavaOpts(p)...)
+	javaOpts = append(javaOpts, bookkeeperTLSJavaOpts(p)...)
+	javaOpts = append(javaOpts, zkAuthJavaOpts(p)...)
+
+	if p.Spec.Pravega.AdminGateway != nil {
+		javaOpts = append(javaOpts,
+			"-Dpravegaservice.admin.gateway.enabled=true",
+			fmt.Sprintf("-Dpravegaservice.admin.gateway.port=%d", adminGatewayPort(p)))
+	}
+
+	if p.Spec.ExternalAccess.IsEnabledForSegmentStore() {
+		advertisedPort := p.Spec.Pravega.SegmentStoreAdvertisedPort
+		if advertisedPort == 0 {
+			advertisedPort = p.Spec.Pravega.SegmentStoreExternalPort
+		}
+		if advertisedPort != 0 {
+			javaOpts = append(javaOpts,
+				fmt.Sprintf("-Dpravegaservice.publishedPort=%d", advertisedPort))
+		}
+	}
+
+	if p.Spec.Pravega.SegmentStoreLogbackConfigMap != "" {
+		javaOpts = append(javaOpts, logbackOpt())
+	}
+
+	javaOpts = append(javaOpts, metricsJavaOpts(p)...)
+	javaOpts = append(javaOpts, debugJavaOpts(p.Spec.Pravega.SegmentStoreDebug)...)
+	if p.Spec.Pravega.SegmentContainerCount != 0 {
+		javaOpts = append(javaOpts,
+			fmt.Sprintf("-Dpravegaservice.containerCount=%d", p.Spec.Pravega.SegmentContainerCount))
+	}
+
+	javaOpts = append(javaOpts, p.Spec.Pravega.SegmentStoreJvmOptions...)
+
+	for name, value := range util.MergeMaps(p.Spec.Pravega.Options, p.Spec.Pravega.SegmentStoreOptions) {
+		if _, _, ok := parseSecretKeyRef(value); ok {
+			// Secret-backed values are injected as env vars and
+			// expanded by the entrypoint, not written to the config map
+			javaOpts = append(javaOpts, fmt.Sprintf("-D%v=$%v", name, secretOptionEnvName(name)))
+			continue
+		}
 		javaOpts = append(javaOpts, fmt.Sprintf("-D%v=%v", name, value))
 	}
 
@@ -172,26 +336,56 @@ func MakeSegmentstoreConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
 		"CONTROLLER_URL":        util.PravegaControllerServiceURL(*p),
 	}
 
-	// Wait for at least 3 Bookies to come up
-	var waitFor []string
-	for i := int32(0); i < util.Min(3, p.Spec.Bookkeeper.Replicas); i++ {
-		waitFor = append(waitFor,
-			fmt.Sprintf("%s-%d.%s.%s:3181",
-				util.StatefulSetNameForBookie(p.Name),
-				i,
-				util.HeadlessServiceNameForBookie(p.Name),
-				p.Namespace))
+	// When the wait init container is enabled it takes over the
+	// dependency check
+	if !p.Spec.DependencyWait.IsEnabled() {
+		configData["WAIT_FOR"] = strings.Join(bookieWaitEndpoints(p), ",")
 	}
-	configData["WAIT_FOR"] = strings.Join(waitFor, ",")
 
-	if p.Spec.ExternalAccess.Enabled {
+	if p.Spec.ExternalAccess.IsEnabledForSegmentStore() {
 		configData["K8_EXTERNAL_ACCESS"] = "true"
+		if p.Spec.ExternalAccess.DomainName != "" {
+			// Pods advertise their external-dns hostname instead of the
+			// raw load balancer address
+			configData["K8_EXTERNAL_DNS_SUFFIX"] = strings.TrimSuffix(p.Spec.ExternalAccess.DomainName, ".")
+		}
+	}
+
+	if p.Spec.Authentication.IsEnabled() {
+		// The signing key env propagated to the pods lets segment stores
+		// authenticate their internal calls to the controller
+		configData["AUTHORIZATION_ENABLED"] = "true"
+	}
+
+	for k, v := range keycloakConfigData(p) {
+		configData[k] = v
+	}
+
+	// The TLS secret is expected to follow the kubernetes.io/tls layout:
+	// "tls.crt" and "tls.key". Under mesh mTLS the sidecars encrypt
+	// traffic and Pravega's own TLS stays off
+	if !p.Spec.IsMeshMTLSEnabled() && p.Spec.TLS.IsSecureSegmentStore() {
+		configData["TLS_ENABLED"] = "true"
+		configData["TLS_CERT_FILE"] = tlsMountDir + "/tls.crt"
+		configData["TLS_KEY_FILE"] = tlsMountDir + "/tls.key"
+	}
+
+	// TLS material rendered by the Vault agent takes precedence over any
+	// mounted secret
+	if !p.Spec.IsMeshMTLSEnabled() && vaultTLSEnabled(p) {
+		configData["TLS_ENABLED"] = "true"
+		configData["TLS_CERT_FILE"] = vaultSecretsDir + "/tls.crt"
+		configData["TLS_KEY_FILE"] = vaultSecretsDir + "/tls.key"
 	}
 
 	if p.Spec.Pravega.DebugLogging {
 		configData["log.level"] = "DEBUG"
 	}
 
+	if p.Spec.Pravega.SegmentStoreLogLevel != "" {
+		configData["log.level"] = p.Spec.Pravega.SegmentStoreLogLevel
+	}
+
 	for k, v := range getTier2StorageOptions(p.Spec.Pravega) {
 		configData[k] = v
 	}
@@ -210,6 +404,21 @@ func MakeSegmentstoreConfigMap(p *api.PravegaCluster) *corev1.ConfigMap {
 	}
 }
 
+// bookieWaitEndpoints returns the endpoints of the Bookies a segment store
+// waits for before starting, capped at 3
+func bookieWaitEndpoints(p *api.PravegaCluster) []string {
+	var waitFor []string
+	for i := int32(0); i < util.Min(3, p.Spec.Bookkeeper.Replicas); i++ {
+		waitFor = append(waitFor,
+			fmt.Sprintf("%s-%d.%s.%s:3181",
+				util.StatefulSetNameForBookie(p.Name),
+				i,
+				util.HeadlessServiceNameForBookie(p.Name),
+				p.Namespace))
+	}
+	return waitFor
+}
+
 func makeCacheVolumeClaimTemplate(pravegaSpec *api.PravegaSpec) []corev1.PersistentVolumeClaim {
 	return []corev1.PersistentVolumeClaim{
 		{
@@ -231,13 +440,23 @@ func getTier2StorageOptions(pravegaSpec *api.PravegaSpec) map[string]string {
 
 	if pravegaSpec.Tier2.Ecs != nil {
 		// EXTENDEDS3_ACCESS_KEY_ID & EXTENDEDS3_SECRET_KEY will come from secret storage
-		return map[string]string{
+		options := map[string]string{
 			"TIER2_STORAGE":        "EXTENDEDS3",
 			"EXTENDEDS3_BUCKET":    pravegaSpec.Tier2.Ecs.Bucket,
 			"EXTENDEDS3_URI":       pravegaSpec.Tier2.Ecs.Uri,
 			"EXTENDEDS3_ROOT":      pravegaSpec.Tier2.Ecs.Root,
 			"EXTENDEDS3_NAMESPACE": pravegaSpec.Tier2.Ecs.Namespace,
 		}
+
+		switch pravegaSpec.Tier2.Ecs.ServerSideEncryption {
+		case "sse-s3":
+			options["EXTENDEDS3_SSE"] = "S3"
+		case "sse-kms":
+			options["EXTENDEDS3_SSE"] = "KMS"
+			options["EXTENDEDS3_SSE_KMS_KEY_ID"] = pravegaSpec.Tier2.Ecs.KmsKeyId
+		}
+
+		return options
 	}
 
 	if pravegaSpec.Tier2.Hdfs != nil {
@@ -252,6 +471,13 @@ func getTier2StorageOptions(pravegaSpec *api.PravegaSpec) map[string]string {
 }
 
 func configureTier2Secrets(environment []corev1.EnvFromSource, pravegaSpec *api.PravegaSpec) []corev1.EnvFromSource {
+	// With ambient credentials, the storage SDK walks the provider
+	// credentials chain set up through workload identity, so no static
+	// keys are injected
+	if pravegaSpec.Tier2.AmbientCredentials {
+		return environment
+	}
+
 	if pravegaSpec.Tier2.Ecs != nil {
 		return append(environment, corev1.EnvFromSource{
 			Prefix: "EXTENDEDS3_",
@@ -284,6 +510,10 @@ func configureTier2Filesystem(podSpec *corev1.PodSpec, pravegaSpec *api.PravegaS
 }
 
 func configureSegmentstoreTLSSecret(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if p.Spec.IsMeshMTLSEnabled() {
+		// The mesh encrypts pod-to-pod traffic; Pravega's own TLS stays off
+		return
+	}
 	if p.Spec.TLS.IsSecureSegmentStore() {
 		vol := corev1.Volume{
 			Name: tlsVolumeName,
@@ -309,50 +539,169 @@ func MakeSegmentStoreHeadlessService(pravegaCluster *api.PravegaCluster) *corev1
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      util.HeadlessServiceNameForSegmentStore(pravegaCluster.Name),
-			Namespace: pravegaCluster.Namespace,
-			Labels:    util.LabelsForSegmentStore(pravegaCluster),
+			Name:        util.HeadlessServiceNameForSegmentStore(pravegaCluster.Name),
+			Namespace:   pravegaCluster.Namespace,
+			Labels:      util.LabelsForSegmentStore(pravegaCluster),
+			Annotations: pravegaCluster.Spec.Pravega.SegmentStoreServiceAnnotations,
 		},
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
+			Ports: append([]corev1.ServicePort{
 				{
-					Name:     "server",
+					Name:     segmentStorePortName(pravegaCluster),
 					Port:     12345,
 					Protocol: "TCP",
 				},
-			},
+			}, metricsServicePorts(pravegaCluster)...),
 			Selector:  util.LabelsForSegmentStore(pravegaCluster),
 			ClusterIP: corev1.ClusterIPNone,
 		},
 	}
 }
 
+func adminGatewayPort(p *api.PravegaCluster) int32 {
+	if p.Spec.Pravega.AdminGateway != nil && p.Spec.Pravega.AdminGateway.Port != 0 {
+		return p.Spec.Pravega.AdminGateway.Port
+	}
+	return defaultAdminPort
+}
+
+// configureAdminGateway opens the admin gateway port on the segment store
+// container and mounts the admin credentials when configured.
+func configureAdminGateway(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if p.Spec.Pravega.AdminGateway == nil {
+		return
+	}
+
+	podSpec.Containers[0].Ports = append(podSpec.Containers[0].Ports, corev1.ContainerPort{
+		Name:          "admin",
+		ContainerPort: adminGatewayPort(p),
+	})
+
+	if p.Spec.Pravega.AdminGateway.CredentialsSecret != "" {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: adminVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: p.Spec.Pravega.AdminGateway.CredentialsSecret,
+				},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      adminVolumeName,
+			MountPath: adminMountDir,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// MakeSegmentStoreAdminService builds an internal-only service in front of
+// the admin gateway listeners.
+func MakeSegmentStoreAdminService(p *api.PravegaCluster) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.AdminServiceNameForSegmentStore(p.Name),
+			Namespace: p.Namespace,
+			Labels:    util.LabelsForSegmentStore(p),
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{
+				{
+					Name:     "admin",
+					Port:     adminGatewayPort(p),
+					Protocol: "TCP",
+				},
+			},
+			Selector: util.LabelsForSegmentStore(p),
+		},
+	}
+}
+
+// substituteServiceTemplate replaces the "{cluster}" and "{ordinal}" tokens
+// used by the per-pod external service templates.
+func substituteServiceTemplate(template string, clusterName string, ordinal int32) string {
+	replacer := strings.NewReplacer(
+		"{cluster}", clusterName,
+		"{ordinal}", fmt.Sprintf("%d", ordinal),
+	)
+	return replacer.Replace(template)
+}
+
 func MakeSegmentStoreExternalServices(pravegaCluster *api.PravegaCluster) []*corev1.Service {
 	var service *corev1.Service
 	services := make([]*corev1.Service, pravegaCluster.Spec.Pravega.SegmentStoreReplicas)
+	baseAnnotations := util.MergeMaps(externalAccessAnnotations(pravegaCluster),
+		pravegaCluster.Spec.Pravega.SegmentStoreServiceAnnotations)
+	externalPort := int32(12345)
+	if pravegaCluster.Spec.Pravega.SegmentStoreExternalPort != 0 {
+		externalPort = pravegaCluster.Spec.Pravega.SegmentStoreExternalPort
+	}
+	var sourceRanges []string
+	if pravegaCluster.Spec.ExternalAccess.Type == corev1.ServiceTypeLoadBalancer {
+		sourceRanges = pravegaCluster.Spec.ExternalAccess.LoadBalancerSourceRanges
+	}
+	trafficPolicy := corev1.ServiceExternalTrafficPolicyTypeLocal
+	if pravegaCluster.Spec.ExternalAccess.ExternalTrafficPolicy != "" {
+		trafficPolicy = pravegaCluster.Spec.ExternalAccess.ExternalTrafficPolicy
+	}
 
 	for i := int32(0); i < pravegaCluster.Spec.Pravega.SegmentStoreReplicas; i++ {
+		var nodePort int32
+		if pravegaCluster.Spec.Pravega.SegmentStoreNodePortBase != 0 {
+			nodePort = pravegaCluster.Spec.Pravega.SegmentStoreNodePortBase + i
+		}
+		var loadBalancerIP string
+		if pravegaCluster.Spec.ExternalAccess.Type == corev1.ServiceTypeLoadBalancer &&
+			int(i) < len(pravegaCluster.Spec.ExternalAccess.SegmentStoreLoadBalancerIPs) {
+			loadBalancerIP = pravegaCluster.Spec.ExternalAccess.SegmentStoreLoadBalancerIPs[i]
+		}
+		serviceName := util.ServiceNameForSegmentStore(pravegaCluster.Name, i)
+		if pravegaCluster.Spec.Pravega.SegmentStoreExternalServiceNameTemplate != "" {
+			serviceName = substituteServiceTemplate(
+				pravegaCluster.Spec.Pravega.SegmentStoreExternalServiceNameTemplate, pravegaCluster.Name, i)
+		}
+		annotations := baseAnnotations
+		if len(pravegaCluster.Spec.Pravega.SegmentStoreExternalServiceAnnotations) > 0 {
+			podAnnotations := map[string]string{}
+			for key, value := range pravegaCluster.Spec.Pravega.SegmentStoreExternalServiceAnnotations {
+				podAnnotations[key] = substituteServiceTemplate(value, pravegaCluster.Name, i)
+			}
+			annotations = util.MergeMaps(baseAnnotations, podAnnotations)
+		}
+		if pravegaCluster.Spec.ExternalAccess.DomainName != "" {
+			annotations = util.MergeMaps(annotations, map[string]string{
+				externalDNSAnnotationKey: util.ExternalDNSHostname(serviceName, pravegaCluster.Spec.ExternalAccess.DomainName),
+			})
+		}
 		service = &corev1.Service{
 			TypeMeta: metav1.TypeMeta{
 				Kind:       "Service",
 				APIVersion: "v1",
 			},
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      util.ServiceNameForSegmentStore(pravegaCluster.Name, i),
-				Namespace: pravegaCluster.Namespace,
-				Labels:    util.LabelsForSegmentStore(pravegaCluster),
+				Name:        serviceName,
+				Namespace:   pravegaCluster.Namespace,
+				Labels:      util.LabelsForSegmentStore(pravegaCluster),
+				Annotations: annotations,
 			},
 			Spec: corev1.ServiceSpec{
 				Type: pravegaCluster.Spec.ExternalAccess.Type,
 				Ports: []corev1.ServicePort{
 					{
-						Name:       "server",
-						Port:       12345,
+						Name:       segmentStorePortName(pravegaCluster),
+						Port:       externalPort,
 						Protocol:   "TCP",
 						TargetPort: intstr.FromInt(12345),
+						NodePort:   nodePort,
 					},
 				},
-				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+				ExternalTrafficPolicy:    trafficPolicy,
+				LoadBalancerSourceRanges: sourceRanges,
+				LoadBalancerIP:           loadBalancerIP,
 				Selector: map[string]string{
 					appsv1.StatefulSetPodNameLabel: fmt.Sprintf("%s-%d", util.StatefulSetNameForSegmentstore(pravegaCluster.Name), i),
 				},
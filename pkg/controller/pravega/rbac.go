@@ -0,0 +1,104 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func MakeControllerServiceAccount(p *api.PravegaCluster) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        p.Spec.Pravega.ControllerServiceAccountName,
+			Namespace:   p.Namespace,
+			Labels:      util.LabelsForController(p),
+			Annotations: p.Spec.Pravega.ControllerServiceAccountAnnotations,
+		},
+	}
+}
+
+func MakeSegmentStoreServiceAccount(p *api.PravegaCluster) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        p.Spec.Pravega.SegmentStoreServiceAccountName,
+			Namespace:   p.Namespace,
+			Labels:      util.LabelsForSegmentStore(p),
+			Annotations: p.Spec.Pravega.SegmentStoreServiceAccountAnnotations,
+		},
+	}
+}
+
+// MakeServiceReaderRole returns a role that allows reading the services of
+// the cluster namespace, which segment stores need to discover the external
+// IP address they should advertise. It is the only API access a Pravega
+// component needs, so nothing broader is generated.
+func MakeServiceReaderRole(p *api.PravegaCluster) *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Role",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.RoleNameForCluster(p.Name),
+			Namespace: p.Namespace,
+			Labels:    util.LabelsForPravegaCluster(p),
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				// Discovering the advertised address only takes the
+				// services themselves, not their endpoints
+				APIGroups: []string{""},
+				Resources: []string{"services"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+}
+
+func MakeServiceReaderRoleBinding(p *api.PravegaCluster) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.RoleBindingNameForCluster(p.Name),
+			Namespace: p.Namespace,
+			Labels:    util.LabelsForPravegaCluster(p),
+		},
+		// Only the segment store reads services; binding the controller
+		// account as well would grant more than it needs
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      p.Spec.Pravega.SegmentStoreServiceAccountName,
+				Namespace: p.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     util.RoleNameForCluster(p.Name),
+		},
+	}
+}
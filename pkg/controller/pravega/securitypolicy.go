@@ -0,0 +1,208 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	"fmt"
+
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// sccGVK identifies the OpenShift SecurityContextConstraints kind, built
+// as unstructured so the operator does not link against the OpenShift API
+var sccGVK = schema.GroupVersionKind{
+	Group:   "security.openshift.io",
+	Version: "v1",
+	Kind:    "SecurityContextConstraints",
+}
+
+// policyVolumeTypes are the only volume types the generated pod specs use
+var policyVolumeTypes = []string{
+	"configMap",
+	"secret",
+	"emptyDir",
+	"persistentVolumeClaim",
+	"downwardAPI",
+	"projected",
+}
+
+// MakePodSecurityPolicy returns a PodSecurityPolicy granting exactly what
+// the Pravega pods need: arbitrary uids and fsGroups for the
+// PersistentVolumeClaims, no privileges and no privilege escalation
+func MakePodSecurityPolicy(p *api.PravegaCluster) *policyv1beta1.PodSecurityPolicy {
+	allowPrivilegeEscalation := false
+
+	volumes := make([]policyv1beta1.FSType, len(policyVolumeTypes))
+	for i, volumeType := range policyVolumeTypes {
+		volumes[i] = policyv1beta1.FSType(volumeType)
+	}
+
+	return &policyv1beta1.PodSecurityPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PodSecurityPolicy",
+			APIVersion: "policy/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   util.SecurityPolicyNameForCluster(p.Name),
+			Labels: util.LabelsForPravegaCluster(p),
+		},
+		Spec: policyv1beta1.PodSecurityPolicySpec{
+			Privileged:               false,
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			Volumes:                  volumes,
+			RunAsUser: policyv1beta1.RunAsUserStrategyOptions{
+				Rule: policyv1beta1.RunAsUserStrategyRunAsAny,
+			},
+			SELinux: policyv1beta1.SELinuxStrategyOptions{
+				Rule: policyv1beta1.SELinuxStrategyRunAsAny,
+			},
+			SupplementalGroups: policyv1beta1.SupplementalGroupsStrategyOptions{
+				Rule: policyv1beta1.SupplementalGroupsStrategyRunAsAny,
+			},
+			FSGroup: policyv1beta1.FSGroupStrategyOptions{
+				Rule: policyv1beta1.FSGroupStrategyRunAsAny,
+			},
+		},
+	}
+}
+
+// MakePolicyUseRole returns a role allowing the component service accounts
+// to use the generated PodSecurityPolicy
+func MakePolicyUseRole(p *api.PravegaCluster) *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Role",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.PolicyRoleNameForCluster(p.Name),
+			Namespace: p.Namespace,
+			Labels:    util.LabelsForPravegaCluster(p),
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{"policy"},
+				Resources:     []string{"podsecuritypolicies"},
+				ResourceNames: []string{util.SecurityPolicyNameForCluster(p.Name)},
+				Verbs:         []string{"use"},
+			},
+		},
+	}
+}
+
+func MakePolicyUseRoleBinding(p *api.PravegaCluster) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.PolicyRoleNameForCluster(p.Name),
+			Namespace: p.Namespace,
+			Labels:    util.LabelsForPravegaCluster(p),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      p.Spec.Pravega.ControllerServiceAccountName,
+				Namespace: p.Namespace,
+			},
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      p.Spec.Pravega.SegmentStoreServiceAccountName,
+				Namespace: p.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     util.PolicyRoleNameForCluster(p.Name),
+		},
+	}
+}
+
+// MakeSecurityContextConstraints returns the OpenShift equivalent of the
+// PodSecurityPolicy. SCCs bind through their users list, so no extra role
+// is needed
+func MakeSecurityContextConstraints(p *api.PravegaCluster) *unstructured.Unstructured {
+	volumes := make([]interface{}, len(policyVolumeTypes))
+	for i, volumeType := range policyVolumeTypes {
+		volumes[i] = volumeType
+	}
+
+	scc := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"allowPrivilegedContainer": false,
+			"allowPrivilegeEscalation": false,
+			"allowHostNetwork":         false,
+			"allowHostPorts":           false,
+			"runAsUser":                map[string]interface{}{"type": "RunAsAny"},
+			"seLinuxContext":           map[string]interface{}{"type": "MustRunAs"},
+			"fsGroup":                  map[string]interface{}{"type": "RunAsAny"},
+			"supplementalGroups":       map[string]interface{}{"type": "RunAsAny"},
+			"volumes":                  volumes,
+			"users": []interface{}{
+				fmt.Sprintf("system:serviceaccount:%s:%s", p.Namespace, p.Spec.Pravega.ControllerServiceAccountName),
+				fmt.Sprintf("system:serviceaccount:%s:%s", p.Namespace, p.Spec.Pravega.SegmentStoreServiceAccountName),
+			},
+		},
+	}
+	scc.SetGroupVersionKind(sccGVK)
+	scc.SetName(util.SecurityPolicyNameForCluster(p.Name))
+	scc.SetLabels(util.LabelsForPravegaCluster(p))
+	return scc
+}
+
+// securityProfileAnnotations translates the configured seccomp and AppArmor
+// profiles into the pod annotations this Kubernetes generation understands.
+// Emitting them from the spec keeps them in place across reconciles, where
+// hand-added annotations on the pods would be stripped
+func securityProfileAnnotations(containerName string, seccompProfile string, apparmorProfile string) map[string]string {
+	annotations := map[string]string{}
+	if seccompProfile != "" {
+		annotations["seccomp.security.alpha.kubernetes.io/pod"] = seccompProfile
+	}
+	if apparmorProfile != "" {
+		annotations["container.apparmor.security.beta.kubernetes.io/"+containerName] = apparmorProfile
+	}
+	return annotations
+}
+
+// configurePodSecurityContext applies the pod-level security context
+// matching the generated policy: the configured fsGroup for volume
+// ownership and no privilege escalation on the main container. An explicit
+// per-component securityContext stays untouched
+func configurePodSecurityContext(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if !p.Spec.SecurityPolicy.IsEnabled() {
+		return
+	}
+
+	if podSpec.SecurityContext == nil && p.Spec.SecurityPolicy.FsGroup != nil {
+		podSpec.SecurityContext = &corev1.PodSecurityContext{
+			FSGroup: p.Spec.SecurityPolicy.FsGroup,
+		}
+	}
+
+	if podSpec.Containers[0].SecurityContext == nil {
+		allowPrivilegeEscalation := false
+		podSpec.Containers[0].SecurityContext = &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		}
+	}
+}
@@ -0,0 +1,81 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	"strings"
+
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// tlsHardeningJavaOpts narrows the protocol versions and cipher suites of
+// the JVM, which both the REST and gRPC listeners inherit. Security scans
+// flag the JDK defaults and there is no Pravega-level switch for them
+func tlsHardeningJavaOpts(p *api.PravegaCluster) []string {
+	if p.Spec.TLS == nil {
+		return nil
+	}
+
+	var opts []string
+	if protocols := api.TLSProtocolVersionsFrom(p.Spec.TLS.MinVersion); len(protocols) > 0 {
+		opts = append(opts, "-Djdk.tls.server.protocols="+strings.Join(protocols, ","))
+	}
+	if len(p.Spec.TLS.CipherSuites) > 0 {
+		opts = append(opts,
+			"-Djdk.tls.server.cipherSuites="+strings.Join(p.Spec.TLS.CipherSuites, ","))
+	}
+	return opts
+}
+
+// mountBookieTLSSecret mounts the bookie TLS secret read-only on the
+// first container. The bookies take their certificate and key from it;
+// the segment stores only need the CA it carries
+func mountBookieTLSSecret(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if !p.Spec.TLS.IsBookkeeperTLSEnabled() {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: bookieTLSVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: p.Spec.TLS.Bookkeeper.BookieSecret,
+			},
+		},
+	})
+
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      bookieTLSVolumeName,
+		MountPath: bookieTLSMountDir,
+		ReadOnly:  true,
+	})
+}
+
+// bookkeeperTLSJavaOpts points the segment stores' BookKeeper client at
+// the bookie CA and, for mutual TLS, at the segment store certificate
+// that configureSegmentstoreTLSSecret already mounts
+func bookkeeperTLSJavaOpts(p *api.PravegaCluster) []string {
+	if !p.Spec.TLS.IsBookkeeperTLSEnabled() {
+		return nil
+	}
+
+	opts := []string{
+		"-Dbookkeeper.tlsEnabled=true",
+		"-Dbookkeeper.tlsTrustStorePath=" + bookieTLSMountDir + "/ca.crt",
+	}
+	if p.Spec.TLS.Bookkeeper.ClientAuthentication && p.Spec.TLS.IsSecureSegmentStore() {
+		opts = append(opts,
+			"-Dbookkeeper.tlsCertificatePath="+tlsMountDir+"/tls.crt",
+			"-Dbookkeeper.tlsKeyStorePath="+tlsMountDir+"/tls.key")
+	}
+	return opts
+}
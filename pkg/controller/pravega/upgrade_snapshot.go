@@ -0,0 +1,104 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	"fmt"
+
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MakeZkSnapshotJob builds the Job that dumps the cluster's ZooKeeper
+// subtree before an upgrade starts. The connection string, the subtree
+// root and the artifact path are passed in the environment; the image
+// brings the snapshot tooling
+func MakeZkSnapshotJob(p *api.PravegaCluster) *batchv1.Job {
+	snapshot := p.Spec.UpgradeSnapshot
+
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers: []corev1.Container{
+			{
+				Name:    "zk-snapshot",
+				Image:   snapshot.Image,
+				Command: snapshot.Command,
+				Env: []corev1.EnvVar{
+					{
+						Name:  "ZOOKEEPER_URI",
+						Value: p.Spec.ZookeeperUri,
+					},
+					{
+						Name:  "SNAPSHOT_PATH",
+						Value: fmt.Sprintf("/%s/%s", util.PravegaPath, p.Name),
+					},
+					{
+						Name:  "SNAPSHOT_FILE",
+						Value: ZkSnapshotArtifact(p),
+					},
+				},
+			},
+		},
+	}
+
+	if snapshot.Volume != nil {
+		podSpec.Volumes = []corev1.Volume{
+			{
+				Name:         snapshotVolumeName,
+				VolumeSource: *snapshot.Volume,
+			},
+		}
+		podSpec.Containers[0].VolumeMounts = []corev1.VolumeMount{
+			{
+				Name:      snapshotVolumeName,
+				MountPath: snapshotMountDir,
+			},
+		}
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.ZkSnapshotJobName(p.Name, p.Spec.Version),
+			Namespace: p.Namespace,
+			// Deliberately not LabelsForPravegaCluster: the Job pod must
+			// not be picked up as a cluster member by the status reconciler
+			Labels: map[string]string{
+				"app":             "zk-snapshot",
+				"pravega_cluster": p.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app":             "zk-snapshot",
+						"pravega_cluster": p.Name,
+					},
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+}
+
+// ZkSnapshotArtifact is the path the snapshot Job is asked to write the
+// artifact to. With a snapshot volume configured, the file survives the
+// Job pod and documents the recovery point of the upgrade
+func ZkSnapshotArtifact(p *api.PravegaCluster) string {
+	return fmt.Sprintf("%s/%s-%s.json", snapshotMountDir, p.Name, p.Spec.Version)
+}
@@ -0,0 +1,64 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	"fmt"
+
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+)
+
+// vaultSecretsDir is where the Vault agent renders the injected files
+const vaultSecretsDir = "/vault/secrets"
+
+// vaultPodAnnotations returns the Vault agent injector annotations that
+// render the configured paths as files inside the pod, for organizations
+// that forbid long-lived Kubernetes Secrets. The injector mutates the pod
+// based on these annotations, no volumes need to be declared here
+func vaultPodAnnotations(p *api.PravegaCluster) map[string]string {
+	if !p.Spec.SecretsProvider.IsVaultEnabled() {
+		return nil
+	}
+	vault := p.Spec.SecretsProvider.Vault
+
+	annotations := map[string]string{
+		"vault.hashicorp.com/agent-inject": "true",
+		"vault.hashicorp.com/role":         vault.Role,
+	}
+	if vault.TLSPath != "" {
+		addVaultFile(annotations, "tls.crt", vault.TLSPath, "certificate")
+		addVaultFile(annotations, "tls.key", vault.TLSPath, "private_key")
+		addVaultFile(annotations, "ca.crt", vault.TLSPath, "issuing_ca")
+	}
+	if vault.TokenSigningKeyPath != "" {
+		addVaultFile(annotations, "token-signing-key", vault.TokenSigningKeyPath, "key")
+	}
+	if vault.Tier2CredentialsPath != "" {
+		annotations["vault.hashicorp.com/agent-inject-secret-tier2-credentials"] = vault.Tier2CredentialsPath
+		annotations["vault.hashicorp.com/agent-inject-template-tier2-credentials"] = fmt.Sprintf(
+			"{{- with secret %q -}}{{- range $field, $value := .Data.data }}{{ $field }}={{ $value }}\n{{ end -}}{{- end -}}",
+			vault.Tier2CredentialsPath)
+	}
+	return annotations
+}
+
+// addVaultFile renders a single field of a KV entry into its own file
+func addVaultFile(annotations map[string]string, file string, path string, field string) {
+	annotations["vault.hashicorp.com/agent-inject-secret-"+file] = path
+	annotations["vault.hashicorp.com/agent-inject-template-"+file] = fmt.Sprintf(
+		"{{- with secret %q -}}{{ .Data.data.%s }}{{- end -}}", path, field)
+}
+
+// vaultTLSEnabled returns whether the TLS material comes from Vault
+// instead of a mounted secret
+func vaultTLSEnabled(p *api.PravegaCluster) bool {
+	return p.Spec.SecretsProvider.IsVaultEnabled() && p.Spec.SecretsProvider.Vault.TLSPath != ""
+}
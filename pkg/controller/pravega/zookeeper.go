@@ -0,0 +1,99 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravega
+
+import (
+	api "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// zkAuthJavaOpts builds the JVM properties that make the ZooKeeper client
+// of a component authenticate its sessions. The digest credentials are
+// referenced through environment variables so they stay out of the config
+// map; the entrypoint shell expands them when assembling the command line
+func zkAuthJavaOpts(p *api.PravegaCluster) []string {
+	if !p.Spec.Zookeeper.IsAuthEnabled() {
+		return nil
+	}
+
+	switch p.Spec.Zookeeper.Auth.Scheme {
+	case "digest":
+		return []string{
+			"-Dzookeeper.client.auth.scheme=digest",
+			"-Dzookeeper.client.auth=$ZK_AUTH_USERNAME:$ZK_AUTH_PASSWORD",
+		}
+	case "sasl":
+		return []string{
+			"-Djava.security.auth.login.config=" + zkAuthMountDir + "/" + zkJaasConfigKey,
+			"-Dzookeeper.sasl.client=true",
+			"-Dzookeeper.sasl.clientconfig=Client",
+		}
+	}
+	return nil
+}
+
+// zkAuthEnvs surfaces the digest credentials from the credentials secret.
+// SASL needs no environment; its material comes in through the JAAS file
+func zkAuthEnvs(p *api.PravegaCluster) []corev1.EnvVar {
+	if !p.Spec.Zookeeper.IsAuthEnabled() || p.Spec.Zookeeper.Auth.Scheme != "digest" {
+		return nil
+	}
+
+	secret := p.Spec.Zookeeper.Auth.CredentialsSecret
+	return []corev1.EnvVar{
+		{
+			Name: "ZK_AUTH_USERNAME",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secret,
+					},
+					Key: "username",
+				},
+			},
+		},
+		{
+			Name: "ZK_AUTH_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secret,
+					},
+					Key: "password",
+				},
+			},
+		},
+	}
+}
+
+// configureZookeeperAuth mounts the JAAS configuration for SASL sessions.
+// Digest credentials travel as environment variables instead
+func configureZookeeperAuth(podSpec *corev1.PodSpec, p *api.PravegaCluster) {
+	if !p.Spec.Zookeeper.IsAuthEnabled() || p.Spec.Zookeeper.Auth.Scheme != "sasl" {
+		return
+	}
+
+	vol := corev1.Volume{
+		Name: zkAuthVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: p.Spec.Zookeeper.Auth.CredentialsSecret,
+			},
+		},
+	}
+	podSpec.Volumes = append(podSpec.Volumes, vol)
+
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      zkAuthVolumeName,
+		MountPath: zkAuthMountDir,
+		ReadOnly:  true,
+	})
+}
@@ -0,0 +1,160 @@
+/**
+ * Copyright (c) 2018 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravegacluster
+
+import (
+	"context"
+
+	pravegav1alpha1 "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/controller/pravega"
+	"github.com/pravega/pravega-operator/pkg/util"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// syncClusterConfig pushes changed configuration to the live config maps
+// and re-stamps the config-hash annotation on the pod templates consuming
+// them, rolling the pods onto the new settings. The config maps are only
+// created during deployment, so without this an option or log level change
+// would sit in the spec while the pods keep running with the data the maps
+// held at creation time
+func (r *ReconcilePravegaCluster) syncClusterConfig(p *pravegav1alpha1.PravegaCluster) (err error) {
+	// An upgrade or rollback rewrites the pod templates itself and manages
+	// the config maps as part of its own orchestration
+	for _, conditionType := range []pravegav1alpha1.ClusterConditionType{
+		pravegav1alpha1.ClusterConditionUpgrading,
+		pravegav1alpha1.ClusterConditionRollback,
+	} {
+		if _, condition := p.Status.GetClusterCondition(conditionType); condition != nil && condition.Status == corev1.ConditionTrue {
+			return nil
+		}
+	}
+
+	if err = r.syncControllerConfig(p); err != nil {
+		return err
+	}
+	return r.syncSegmentStoreConfig(p)
+}
+
+func (r *ReconcilePravegaCluster) syncControllerConfig(p *pravegav1alpha1.PravegaCluster) (err error) {
+	desired := pravega.MakeControllerConfigMap(p)
+	hash := util.ConfigMapHash(desired.Data)
+
+	current := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(),
+		types.NamespacedName{Name: desired.Name, Namespace: p.Namespace}, current)
+	if err != nil {
+		return err
+	}
+
+	deploy := &appsv1.Deployment{}
+	err = r.client.Get(context.TODO(),
+		types.NamespacedName{Name: util.DeploymentNameForController(p.Name), Namespace: p.Namespace}, deploy)
+	if err != nil {
+		return err
+	}
+
+	if util.ConfigMapHash(current.Data) == hash &&
+		deploy.Spec.Template.Annotations[pravega.ConfigHashAnnotation] == hash {
+		return nil
+	}
+
+	// Rolling the pods is disruptive, hold the change like the other
+	// disruptive operations
+	if !p.InMaintenanceWindow() {
+		p.Status.QueueMaintenance("roll controller pods for changed configuration")
+		return nil
+	}
+
+	current.Data = desired.Data
+	if err = r.client.Update(context.TODO(), current); err != nil {
+		return err
+	}
+
+	if deploy.Spec.Template.Annotations == nil {
+		deploy.Spec.Template.Annotations = map[string]string{}
+	}
+	deploy.Spec.Template.Annotations[pravega.ConfigHashAnnotation] = hash
+	log.Printf("configuration of cluster %s changed, rolling controller pods", p.Name)
+	return r.client.Update(context.TODO(), deploy)
+}
+
+// configRollComplete reports whether the controller and segment store pods
+// all run with the configuration generated from the spec: the templates
+// carry its hash and no pods are left to roll. Until then a config change
+// is still pending, held for the maintenance window or rolling out
+func (r *ReconcilePravegaCluster) configRollComplete(p *pravegav1alpha1.PravegaCluster) bool {
+	deploy := &appsv1.Deployment{}
+	err := r.client.Get(context.TODO(),
+		types.NamespacedName{Name: util.DeploymentNameForController(p.Name), Namespace: p.Namespace}, deploy)
+	if err != nil {
+		return false
+	}
+	if deploy.Spec.Template.Annotations[pravega.ConfigHashAnnotation] != util.ConfigMapHash(pravega.MakeControllerConfigMap(p).Data) ||
+		deploy.Status.UpdatedReplicas != deploy.Status.Replicas ||
+		deploy.Status.ReadyReplicas != deploy.Status.Replicas {
+		return false
+	}
+
+	sts := &appsv1.StatefulSet{}
+	err = r.client.Get(context.TODO(),
+		types.NamespacedName{Name: util.StatefulSetNameForSegmentstore(p.Name), Namespace: p.Namespace}, sts)
+	if err != nil {
+		return false
+	}
+	return sts.Spec.Template.Annotations[pravega.ConfigHashAnnotation] == util.ConfigMapHash(pravega.MakeSegmentstoreConfigMap(p).Data) &&
+		sts.Status.UpdatedReplicas == sts.Status.Replicas &&
+		sts.Status.ReadyReplicas == sts.Status.Replicas
+}
+
+func (r *ReconcilePravegaCluster) syncSegmentStoreConfig(p *pravegav1alpha1.PravegaCluster) (err error) {
+	desired := pravega.MakeSegmentstoreConfigMap(p)
+	hash := util.ConfigMapHash(desired.Data)
+
+	current := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(),
+		types.NamespacedName{Name: desired.Name, Namespace: p.Namespace}, current)
+	if err != nil {
+		return err
+	}
+
+	sts := &appsv1.StatefulSet{}
+	err = r.client.Get(context.TODO(),
+		types.NamespacedName{Name: util.StatefulSetNameForSegmentstore(p.Name), Namespace: p.Namespace}, sts)
+	if err != nil {
+		return err
+	}
+
+	if util.ConfigMapHash(current.Data) == hash &&
+		sts.Spec.Template.Annotations[pravega.ConfigHashAnnotation] == hash {
+		return nil
+	}
+
+	if !p.InMaintenanceWindow() {
+		p.Status.QueueMaintenance("roll segment store pods for changed configuration")
+		return nil
+	}
+
+	current.Data = desired.Data
+	if err = r.client.Update(context.TODO(), current); err != nil {
+		return err
+	}
+
+	if sts.Spec.Template.Annotations == nil {
+		sts.Spec.Template.Annotations = map[string]string{}
+	}
+	sts.Spec.Template.Annotations[pravega.ConfigHashAnnotation] = hash
+	log.Printf("configuration of cluster %s changed, rolling segment store pods", p.Name)
+	return r.client.Update(context.TODO(), sts)
+}
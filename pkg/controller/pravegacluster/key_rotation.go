@@ -0,0 +1,178 @@
+/**
+ * Copyright (c) 2018 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravegacluster
+
+import (
+	"context"
+
+	pravegav1alpha1 "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// syncTokenSigningKey drives a dual-key rotation of the token signing key
+// whenever a replacement is staged in NextTokenSigningKeySecret. The
+// segment stores roll first with both keys, so tokens signed by either
+// validate everywhere; the controller then switches issuance to the new
+// key; finally the old key is retired from the segment stores. A plain
+// swap of TokenSigningKeySecret would instead invalidate every
+// outstanding token and take the whole cluster's auth down at once.
+// Completion is tracked in status.activeTokenSigningKey — the spec stays
+// untouched, promoting the new key there is up to the user
+func (r *ReconcilePravegaCluster) syncTokenSigningKey(p *pravegav1alpha1.PravegaCluster) (err error) {
+	if p.Spec.Authentication == nil || p.Spec.Authentication.TokenSigningKeySecret == "" {
+		return nil
+	}
+
+	if p.Spec.Authentication.NextTokenSigningKeySecret == "" {
+		// No rotation staged, the generated manifests define the key
+		p.Status.ActiveTokenSigningKey = p.Spec.Authentication.TokenSigningKeySecret
+		return nil
+	}
+
+	if p.Status.ActiveTokenSigningKey == p.Spec.Authentication.NextTokenSigningKeySecret {
+		// Rotation already completed; waiting for the user to promote the
+		// staged secret in the spec. Without this guard a spec managed by
+		// an apply pipeline would restart the rotation on every reconcile
+		return nil
+	}
+
+	// An upgrade or rollback already rolls every pod; let it finish
+	// before interleaving the rotation's template changes
+	for _, conditionType := range []pravegav1alpha1.ClusterConditionType{
+		pravegav1alpha1.ClusterConditionUpgrading,
+		pravegav1alpha1.ClusterConditionRollback,
+	} {
+		if _, condition := p.Status.GetClusterCondition(conditionType); condition != nil && condition.Status == corev1.ConditionTrue {
+			return nil
+		}
+	}
+
+	oldKey := p.Spec.Authentication.TokenSigningKeySecret
+	newKey := p.Spec.Authentication.NextTokenSigningKeySecret
+
+	sts := &appsv1.StatefulSet{}
+	err = r.client.Get(context.TODO(),
+		types.NamespacedName{Name: util.StatefulSetNameForSegmentstore(p.Name), Namespace: p.Namespace}, sts)
+	if err != nil {
+		return err
+	}
+
+	// Phase 1: distribute the staged key to the segment stores alongside
+	// the old one. A rotation that has not started yet waits for the
+	// maintenance window; one already under way runs to completion, a
+	// half-rotated cluster should not linger
+	if setEnvSecretRef(&sts.Spec.Template.Spec.Containers[0], "TOKEN_SIGNING_KEY_NEXT", newKey) {
+		if !p.InMaintenanceWindow() {
+			p.Status.QueueMaintenance("rotate the token signing key")
+			return nil
+		}
+		log.Printf("Distributing new token signing key to segment stores of cluster %s", p.Name)
+		return r.client.Update(context.TODO(), sts)
+	}
+
+	if sts.Status.UpdatedReplicas != sts.Status.Replicas ||
+		sts.Status.ReadyReplicas != sts.Status.Replicas {
+		// Still rolling, the controller must keep signing with the old key
+		return nil
+	}
+
+	deploy := &appsv1.Deployment{}
+	err = r.client.Get(context.TODO(),
+		types.NamespacedName{Name: util.DeploymentNameForController(p.Name), Namespace: p.Namespace}, deploy)
+	if err != nil {
+		return err
+	}
+
+	// Phase 2: every segment store accepts both keys, switch the
+	// controller's issuance over to the new one
+	if setEnvSecretRef(&deploy.Spec.Template.Spec.Containers[0], "TOKEN_SIGNING_KEY", newKey) {
+		log.Printf("Switching token issuance of cluster %s to the new signing key", p.Name)
+		return r.client.Update(context.TODO(), deploy)
+	}
+
+	if deploy.Status.UpdatedReplicas != deploy.Status.Replicas ||
+		deploy.Status.ReadyReplicas != deploy.Status.Replicas {
+		return nil
+	}
+
+	// Phase 3: no component issues with the old key anymore, retire it
+	// from the segment stores
+	changed := setEnvSecretRef(&sts.Spec.Template.Spec.Containers[0], "TOKEN_SIGNING_KEY", newKey)
+	if removeEnv(&sts.Spec.Template.Spec.Containers[0], "TOKEN_SIGNING_KEY_NEXT") {
+		changed = true
+	}
+	if changed {
+		log.Printf("Retiring old token signing key %s from cluster %s", oldKey, p.Name)
+		return r.client.Update(context.TODO(), sts)
+	}
+
+	// Rotation converged; record the key in use so the rotation does not
+	// restart. The spec belongs to the user: it keeps naming the retired
+	// key until they promote the staged secret themselves
+	p.Status.ActiveTokenSigningKey = newKey
+	log.Printf("Token signing key rotation of cluster %s complete, %s can be promoted to tokenSigningKeySecret", p.Name, newKey)
+	return nil
+}
+
+// setEnvSecretRef points the named env var of the container at the
+// TOKEN_SIGNING_KEY key of the given secret, adding the var when missing.
+// It reports whether anything changed
+func setEnvSecretRef(container *corev1.Container, envName string, secretName string) bool {
+	for i := range container.Env {
+		if container.Env[i].Name != envName {
+			continue
+		}
+		ref := container.Env[i].ValueFrom
+		if ref != nil && ref.SecretKeyRef != nil && ref.SecretKeyRef.Name == secretName {
+			return false
+		}
+		container.Env[i].ValueFrom = &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretName,
+				},
+				Key: "TOKEN_SIGNING_KEY",
+			},
+		}
+		container.Env[i].Value = ""
+		return true
+	}
+
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name: envName,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretName,
+				},
+				Key: "TOKEN_SIGNING_KEY",
+			},
+		},
+	})
+	return true
+}
+
+// removeEnv drops the named env var from the container, reporting whether
+// it was present
+func removeEnv(container *corev1.Container, envName string) bool {
+	for i := range container.Env {
+		if container.Env[i].Name == envName {
+			container.Env = append(container.Env[:i], container.Env[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -13,6 +13,7 @@ package pravegacluster
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	pravegav1alpha1 "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
@@ -65,6 +66,12 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch the TLS secrets so certificate renewals trigger a reconcile
+	err = watchTLSSecrets(mgr, c)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -122,6 +129,10 @@ func (r *ReconcilePravegaCluster) Reconcile(request reconcile.Request) (reconcil
 }
 
 func (r *ReconcilePravegaCluster) run(p *pravegav1alpha1.PravegaCluster) (err error) {
+	// Rebuilt by the maintenance-window gates below; a change that went
+	// through or was withdrawn no longer shows as pending
+	p.Status.PendingMaintenance = nil
+
 	// Clean up zookeeper metadata
 	err = r.reconcileFinalizers(p)
 	if err != nil {
@@ -138,11 +149,31 @@ func (r *ReconcilePravegaCluster) run(p *pravegav1alpha1.PravegaCluster) (err er
 		return fmt.Errorf("failed to sync cluster size: %v", err)
 	}
 
+	err = r.syncClusterConfig(p)
+	if err != nil {
+		return fmt.Errorf("failed to sync cluster configuration: %v", err)
+	}
+
 	err = r.syncClusterVersion(p)
 	if err != nil {
 		return fmt.Errorf("failed to sync cluster version: %v", err)
 	}
 
+	err = r.syncRollback(p)
+	if err != nil {
+		return fmt.Errorf("failed to roll back cluster version: %v", err)
+	}
+
+	err = r.syncTLSCertificates(p)
+	if err != nil {
+		return fmt.Errorf("failed to sync tls certificates: %v", err)
+	}
+
+	err = r.syncTokenSigningKey(p)
+	if err != nil {
+		return fmt.Errorf("failed to sync token signing key: %v", err)
+	}
+
 	err = r.reconcileClusterStatus(p)
 	if err != nil {
 		return fmt.Errorf("failed to reconcile cluster status: %v", err)
@@ -151,12 +182,36 @@ func (r *ReconcilePravegaCluster) run(p *pravegav1alpha1.PravegaCluster) (err er
 }
 
 func (r *ReconcilePravegaCluster) deployCluster(p *pravegav1alpha1.PravegaCluster) (err error) {
+	if p.Spec.Pravega.CreateServiceAccounts {
+		err = r.deployServiceAccounts(p)
+		if err != nil {
+			log.Printf("failed to deploy service accounts: %v", err)
+			return err
+		}
+	}
+
+	if p.Spec.SecurityPolicy.IsEnabled() {
+		err = r.deploySecurityPolicy(p)
+		if err != nil {
+			log.Printf("failed to deploy security policy: %v", err)
+			return err
+		}
+	}
+
 	err = r.deployBookie(p)
 	if err != nil {
 		log.Printf("failed to deploy bookie: %v", err)
 		return err
 	}
 
+	if p.Spec.TLS.IsCertManagerEnabled() {
+		err = r.deployCertificates(p)
+		if err != nil {
+			log.Printf("failed to deploy certificates: %v", err)
+			return err
+		}
+	}
+
 	err = r.deployController(p)
 	if err != nil {
 		log.Printf("failed to deploy controller: %v", err)
@@ -168,6 +223,130 @@ func (r *ReconcilePravegaCluster) deployCluster(p *pravegav1alpha1.PravegaCluste
 		log.Printf("failed to deploy segment store: %v", err)
 		return err
 	}
+
+	if p.Spec.NetworkPolicy != nil {
+		err = r.deployNetworkPolicies(p)
+		if err != nil {
+			log.Printf("failed to deploy network policies: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcilePravegaCluster) deployNetworkPolicies(p *pravegav1alpha1.PravegaCluster) (err error) {
+	policies := pravega.MakeNetworkPolicies(p)
+	for _, policy := range policies {
+		controllerutil.SetControllerReference(p, policy, r.scheme)
+		err = r.client.Create(context.TODO(), policy)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deploySecurityPolicy creates the policy object granting what the pods
+// need and binds it to the component service accounts. The SCC and the
+// PodSecurityPolicy are cluster-scoped and cannot carry an owner reference
+// to the namespaced cluster, so they are created without one
+func (r *ReconcilePravegaCluster) deploySecurityPolicy(p *pravegav1alpha1.PravegaCluster) (err error) {
+	if p.Spec.SecurityPolicy.Type == "scc" {
+		scc := pravega.MakeSecurityContextConstraints(p)
+		err = r.client.Create(context.TODO(), scc)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	}
+
+	psp := pravega.MakePodSecurityPolicy(p)
+	err = r.client.Create(context.TODO(), psp)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	role := pravega.MakePolicyUseRole(p)
+	controllerutil.SetControllerReference(p, role, r.scheme)
+	err = r.client.Create(context.TODO(), role)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	roleBinding := pravega.MakePolicyUseRoleBinding(p)
+	controllerutil.SetControllerReference(p, roleBinding, r.scheme)
+	err = r.client.Create(context.TODO(), roleBinding)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// deployCertificates asks cert-manager for the controller and segment store
+// certificates and blocks the rollout until the issued secrets exist, so the
+// pods never start with empty TLS mounts
+func (r *ReconcilePravegaCluster) deployCertificates(p *pravegav1alpha1.PravegaCluster) (err error) {
+	controllerCert := pravega.MakeControllerCertificate(p)
+	controllerutil.SetControllerReference(p, controllerCert, r.scheme)
+	err = r.client.Create(context.TODO(), controllerCert)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	segmentStoreCert := pravega.MakeSegmentStoreCertificate(p)
+	controllerutil.SetControllerReference(p, segmentStoreCert, r.scheme)
+	err = r.client.Create(context.TODO(), segmentStoreCert)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	for _, secretName := range []string{
+		p.Spec.TLS.Static.ControllerSecret,
+		p.Spec.TLS.Static.SegmentStoreSecret,
+	} {
+		secret := &corev1.Secret{}
+		err = r.client.Get(context.TODO(),
+			types.NamespacedName{Name: secretName, Namespace: p.Namespace}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return fmt.Errorf("waiting for cert-manager to issue secret %s", secretName)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcilePravegaCluster) deployServiceAccounts(p *pravegav1alpha1.PravegaCluster) (err error) {
+	controllerServiceAccount := pravega.MakeControllerServiceAccount(p)
+	controllerutil.SetControllerReference(p, controllerServiceAccount, r.scheme)
+	err = r.client.Create(context.TODO(), controllerServiceAccount)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	segmentStoreServiceAccount := pravega.MakeSegmentStoreServiceAccount(p)
+	controllerutil.SetControllerReference(p, segmentStoreServiceAccount, r.scheme)
+	err = r.client.Create(context.TODO(), segmentStoreServiceAccount)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	role := pravega.MakeServiceReaderRole(p)
+	controllerutil.SetControllerReference(p, role, r.scheme)
+	err = r.client.Create(context.TODO(), role)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	roleBinding := pravega.MakeServiceReaderRoleBinding(p)
+	controllerutil.SetControllerReference(p, roleBinding, r.scheme)
+	err = r.client.Create(context.TODO(), roleBinding)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
 	return nil
 }
 
@@ -179,6 +358,15 @@ func (r *ReconcilePravegaCluster) deployController(p *pravegav1alpha1.PravegaClu
 		return err
 	}
 
+	if p.Spec.Pravega.ControllerSecretName == "" {
+		secret := pravega.MakeControllerSecret(p)
+		controllerutil.SetControllerReference(p, secret, r.scheme)
+		err = r.client.Create(context.TODO(), secret)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
 	configMap := pravega.MakeControllerConfigMap(p)
 	controllerutil.SetControllerReference(p, configMap, r.scheme)
 	err = r.client.Create(context.TODO(), configMap)
@@ -186,6 +374,15 @@ func (r *ReconcilePravegaCluster) deployController(p *pravegav1alpha1.PravegaClu
 		return err
 	}
 
+	if p.Spec.Pravega.ControllerAuditLog.IsEnabled() {
+		auditConfigMap := pravega.MakeControllerAuditLogbackConfigMap(p)
+		controllerutil.SetControllerReference(p, auditConfigMap, r.scheme)
+		err = r.client.Create(context.TODO(), auditConfigMap)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
 	deployment := pravega.MakeControllerDeployment(p)
 	controllerutil.SetControllerReference(p, deployment, r.scheme)
 	err = r.client.Create(context.TODO(), deployment)
@@ -193,11 +390,60 @@ func (r *ReconcilePravegaCluster) deployController(p *pravegav1alpha1.PravegaClu
 		return err
 	}
 
-	service := pravega.MakeControllerService(p)
-	controllerutil.SetControllerReference(p, service, r.scheme)
-	err = r.client.Create(context.TODO(), service)
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return err
+	if p.Spec.Pravega.SplitControllerServices {
+		services := pravega.MakeControllerSplitServices(p)
+		for _, service := range services {
+			controllerutil.SetControllerReference(p, service, r.scheme)
+			err = r.client.Create(context.TODO(), service)
+			if err != nil && !errors.IsAlreadyExists(err) {
+				return err
+			}
+		}
+	} else {
+		service := pravega.MakeControllerService(p)
+		controllerutil.SetControllerReference(p, service, r.scheme)
+		err = r.client.Create(context.TODO(), service)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	if p.Spec.Pravega.CreateControllerHeadlessService {
+		headlessService := pravega.MakeControllerHeadlessService(p)
+		controllerutil.SetControllerReference(p, headlessService, r.scheme)
+		err = r.client.Create(context.TODO(), headlessService)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	if p.Spec.Pravega.ControllerDebug != nil {
+		debugService := pravega.MakeControllerDebugService(p)
+		controllerutil.SetControllerReference(p, debugService, r.scheme)
+		err = r.client.Create(context.TODO(), debugService)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	if p.Spec.ExternalAccess.Ingress != nil {
+		ingress := pravega.MakeControllerIngress(p)
+		controllerutil.SetControllerReference(p, ingress, r.scheme)
+		err = r.client.Create(context.TODO(), ingress)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	if p.Spec.ExternalAccess.Routes != nil {
+		routes := pravega.MakeControllerRoutes(p)
+		for _, route := range routes {
+			controllerutil.SetControllerReference(p, route, r.scheme)
+			err = r.client.Create(context.TODO(), route)
+			if err != nil && !errors.IsAlreadyExists(err) {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -212,7 +458,7 @@ func (r *ReconcilePravegaCluster) deploySegmentStore(p *pravegav1alpha1.PravegaC
 		return err
 	}
 
-	if p.Spec.ExternalAccess.Enabled {
+	if p.Spec.ExternalAccess.IsEnabledForSegmentStore() {
 		services := pravega.MakeSegmentStoreExternalServices(p)
 		for _, service := range services {
 			controllerutil.SetControllerReference(p, service, r.scheme)
@@ -223,6 +469,24 @@ func (r *ReconcilePravegaCluster) deploySegmentStore(p *pravegav1alpha1.PravegaC
 		}
 	}
 
+	if p.Spec.Pravega.AdminGateway != nil {
+		adminService := pravega.MakeSegmentStoreAdminService(p)
+		controllerutil.SetControllerReference(p, adminService, r.scheme)
+		err = r.client.Create(context.TODO(), adminService)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	if p.Spec.Pravega.SegmentStoreDebug != nil {
+		debugService := pravega.MakeSegmentStoreDebugService(p)
+		controllerutil.SetControllerReference(p, debugService, r.scheme)
+		err = r.client.Create(context.TODO(), debugService)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
 	pdb := pravega.MakeSegmentstorePodDisruptionBudget(p)
 	controllerutil.SetControllerReference(p, pdb, r.scheme)
 	err = r.client.Create(context.TODO(), pdb)
@@ -313,6 +577,10 @@ func (r *ReconcilePravegaCluster) syncBookieSize(p *pravegav1alpha1.PravegaClust
 	}
 
 	if *sts.Spec.Replicas != p.Spec.Bookkeeper.Replicas {
+		if p.Spec.Bookkeeper.Replicas < *sts.Spec.Replicas && !p.InMaintenanceWindow() {
+			p.Status.QueueMaintenance(fmt.Sprintf("scale bookkeeper down to %d replicas", p.Spec.Bookkeeper.Replicas))
+			return nil
+		}
 		sts.Spec.Replicas = &(p.Spec.Bookkeeper.Replicas)
 		err = r.client.Update(context.TODO(), sts)
 		if err != nil {
@@ -336,6 +604,10 @@ func (r *ReconcilePravegaCluster) syncSegmentStoreSize(p *pravegav1alpha1.Praveg
 	}
 
 	if *sts.Spec.Replicas != p.Spec.Pravega.SegmentStoreReplicas {
+		if p.Spec.Pravega.SegmentStoreReplicas < *sts.Spec.Replicas && !p.InMaintenanceWindow() {
+			p.Status.QueueMaintenance(fmt.Sprintf("scale segment store down to %d replicas", p.Spec.Pravega.SegmentStoreReplicas))
+			return nil
+		}
 		sts.Spec.Replicas = &(p.Spec.Pravega.SegmentStoreReplicas)
 		err = r.client.Update(context.TODO(), sts)
 		if err != nil {
@@ -359,6 +631,10 @@ func (r *ReconcilePravegaCluster) syncControllerSize(p *pravegav1alpha1.PravegaC
 	}
 
 	if *deploy.Spec.Replicas != p.Spec.Pravega.ControllerReplicas {
+		if p.Spec.Pravega.ControllerReplicas < *deploy.Spec.Replicas && !p.InMaintenanceWindow() {
+			p.Status.QueueMaintenance(fmt.Sprintf("scale controller down to %d replicas", p.Spec.Pravega.ControllerReplicas))
+			return nil
+		}
 		deploy.Spec.Replicas = &(p.Spec.Pravega.ControllerReplicas)
 		err = r.client.Update(context.TODO(), deploy)
 		if err != nil {
@@ -477,6 +753,25 @@ func (r *ReconcilePravegaCluster) reconcileClusterStatus(p *pravegav1alpha1.Prav
 	p.Status.Members.Ready = readyMembers
 	p.Status.Members.Unready = unreadyMembers
 
+	// The configured levels are applied through the config maps and the
+	// config-hash pod roll; a ready cluster alone proves nothing, the roll
+	// may still be queued for the maintenance window. Only reflect the
+	// levels once every pod runs the configuration generated from the spec
+	if len(readyMembers) == expectedSize && r.configRollComplete(p) {
+		p.Status.ControllerLogLevel = p.Spec.Pravega.ControllerLogLevel
+		p.Status.SegmentStoreLogLevel = p.Spec.Pravega.SegmentStoreLogLevel
+	}
+
+	// The admission webhook notes the option migrations it applied on the
+	// annotation; it cannot write the status subresource itself
+	if rewrites := p.Annotations[pravegav1alpha1.OptionRewritesAnnotation]; rewrites != "" {
+		for _, rewrite := range strings.Split(rewrites, ",") {
+			if !util.ContainsString(p.Status.OptionRewrites, rewrite) {
+				p.Status.OptionRewrites = append(p.Status.OptionRewrites, rewrite)
+			}
+		}
+	}
+
 	err = r.client.Status().Update(context.TODO(), p)
 	if err != nil {
 		return fmt.Errorf("failed to update cluster status: %v", err)
@@ -13,6 +13,7 @@ package pravegacluster
 import (
 	"context"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 
@@ -402,5 +403,218 @@ var _ = Describe("PravegaCluster Controller", func() {
 				})
 			})
 		})
+
+		Context("Canary upgrade", func() {
+			var (
+				client  client.Client
+				err     error
+				synced  bool
+				foundSS *appsv1.StatefulSet
+				ssName  types.NamespacedName
+			)
+
+			BeforeEach(func() {
+				p.Spec = v1alpha1.ClusterSpec{
+					Version: "0.5.0",
+					Pravega: &v1alpha1.PravegaSpec{
+						SegmentStoreReplicas: 3,
+						SegmentStoreCanaryUpgrade: &v1alpha1.CanaryUpgradeSpec{
+							Enabled: true,
+						},
+					},
+					TLS: &v1alpha1.TLSPolicy{
+						Static: &v1alpha1.StaticTLS{
+							// A secure controller makes the upgrade skip the
+							// REST health probe, which cannot answer in tests
+							ControllerSecret: "controller-secret",
+						},
+					},
+				}
+				p.WithDefaults()
+				client = fake.NewFakeClient(p)
+				r = &ReconcilePravegaCluster{client: client, scheme: s}
+				_, err = r.Reconcile(req)
+				Ω(err).Should(BeNil())
+
+				// Simulate an upgrade in flight: the spec already carries the
+				// requested version, the status still points at the running one
+				p.Spec.Version = "0.6.0"
+				p.Status.SetUpgradingConditionTrue()
+				p.Status.CurrentVersion = "0.5.0"
+				p.Status.TargetVersion = "0.6.0"
+
+				foundSS = &appsv1.StatefulSet{}
+				ssName = types.NamespacedName{
+					Name:      util.StatefulSetNameForSegmentstore(p.Name),
+					Namespace: Namespace,
+				}
+			})
+
+			Context("Template update", func() {
+				BeforeEach(func() {
+					synced, err = r.syncSegmentStoreVersion(p)
+					Ω(err).Should(BeNil())
+					foundSS = &appsv1.StatefulSet{}
+					err = client.Get(context.TODO(), ssName, foundSS)
+					Ω(err).Should(BeNil())
+				})
+
+				It("should not be synced yet", func() {
+					Ω(synced).Should(BeFalse())
+				})
+
+				It("should move the template to the target image", func() {
+					Ω(foundSS.Spec.Template.Spec.Containers[0].Image).Should(Equal("pravega/pravega:0.6.0"))
+				})
+
+				It("should hold every pod but the canary back", func() {
+					Ω(foundSS.Spec.UpdateStrategy.RollingUpdate).ShouldNot(BeNil())
+					Ω(*foundSS.Spec.UpdateStrategy.RollingUpdate.Partition).Should(BeEquivalentTo(2))
+				})
+			})
+
+			Context("Canary pod not ready yet", func() {
+				BeforeEach(func() {
+					_, err = r.syncSegmentStoreVersion(p)
+					Ω(err).Should(BeNil())
+					// 2nd iteration: template already updated, no pod has
+					// come up on the target version yet
+					synced, err = r.syncSegmentStoreVersion(p)
+					Ω(err).Should(BeNil())
+				})
+
+				It("should wait for the canary", func() {
+					Ω(synced).Should(BeFalse())
+					_, condition := p.Status.GetClusterCondition(v1alpha1.ClusterConditionUpgrading)
+					Ω(condition.Message).Should(Equal("waiting for the canary segment store pod to become ready on the new version"))
+				})
+			})
+
+			Context("Canary pod ready", func() {
+				BeforeEach(func() {
+					_, err = r.syncSegmentStoreVersion(p)
+					Ω(err).Should(BeNil())
+					foundSS = &appsv1.StatefulSet{}
+					err = client.Get(context.TODO(), ssName, foundSS)
+					Ω(err).Should(BeNil())
+					foundSS.Status.Replicas = 3
+					foundSS.Status.UpdatedReplicas = 1
+					err = client.Update(context.TODO(), foundSS)
+					Ω(err).Should(BeNil())
+
+					synced, err = r.syncSegmentStoreVersion(p)
+					Ω(err).Should(BeNil())
+					foundSS = &appsv1.StatefulSet{}
+					err = client.Get(context.TODO(), ssName, foundSS)
+					Ω(err).Should(BeNil())
+				})
+
+				It("should start the soak clock", func() {
+					Ω(synced).Should(BeFalse())
+					Ω(foundSS.Annotations["pravega.io/canary-soak-start"]).ShouldNot(Equal(""))
+				})
+
+				Context("Soak period over", func() {
+					BeforeEach(func() {
+						foundSS.Annotations["pravega.io/canary-soak-start"] =
+							time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+						err = client.Update(context.TODO(), foundSS)
+						Ω(err).Should(BeNil())
+
+						synced, err = r.syncSegmentStoreVersion(p)
+						Ω(err).Should(BeNil())
+						foundSS = &appsv1.StatefulSet{}
+						err = client.Get(context.TODO(), ssName, foundSS)
+						Ω(err).Should(BeNil())
+					})
+
+					It("should release the remaining pods", func() {
+						Ω(foundSS.Spec.UpdateStrategy.RollingUpdate).Should(BeNil())
+						Ω(foundSS.Annotations).ShouldNot(HaveKey("pravega.io/canary-soak-start"))
+					})
+				})
+			})
+		})
+
+		Context("Rollback", func() {
+			var (
+				client client.Client
+				err    error
+			)
+
+			BeforeEach(func() {
+				p.Spec = v1alpha1.ClusterSpec{
+					Version: "0.5.0",
+					TLS: &v1alpha1.TLSPolicy{
+						Static: &v1alpha1.StaticTLS{
+							ControllerSecret: "controller-secret",
+						},
+					},
+				}
+				p.WithDefaults()
+				client = fake.NewFakeClient(p)
+				r = &ReconcilePravegaCluster{client: client, scheme: s}
+				_, err = r.Reconcile(req)
+				Ω(err).Should(BeNil())
+
+				// Simulate an upgrade to 0.6.0 that failed and reverted the
+				// spec, the state clearUpgradeStatus leaves behind
+				p.Status.CurrentVersion = "0.5.0"
+				p.Status.PreviousVersion = "0.5.0"
+				p.Status.SetUpgradingConditionFalse()
+				p.Status.SetErrorConditionTrue("UpgradeFailed", "failed to sync segmentstore version")
+				p.Status.StartVersionTransition("0.5.0", "0.6.0")
+				p.Status.FinishVersionTransition(v1alpha1.TransitionFailed, "failed to sync segmentstore version", nil)
+			})
+
+			Context("Trigger", func() {
+				BeforeEach(func() {
+					err = r.syncRollback(p)
+					Ω(err).Should(BeNil())
+				})
+
+				It("should set the rollback condition", func() {
+					_, condition := p.Status.GetClusterCondition(v1alpha1.ClusterConditionRollback)
+					Ω(condition).ShouldNot(BeNil())
+					Ω(condition.Status).Should(Equal(corev1.ConditionTrue))
+				})
+
+				It("should target the previous version", func() {
+					Ω(p.Status.TargetVersion).Should(Equal("0.5.0"))
+				})
+
+				It("should open a history record from the failed version", func() {
+					last := p.Status.VersionHistory[len(p.Status.VersionHistory)-1]
+					Ω(last.Outcome).Should(Equal(v1alpha1.TransitionInProgress))
+					Ω(last.FromVersion).Should(Equal("0.6.0"))
+					Ω(last.ToVersion).Should(Equal("0.5.0"))
+				})
+
+				Context("Components rolled back", func() {
+					BeforeEach(func() {
+						// 2nd iteration: every component already runs the
+						// previous image, so the rollback completes
+						err = r.syncRollback(p)
+						Ω(err).Should(BeNil())
+					})
+
+					It("should clear the rollback and error conditions", func() {
+						_, rollbackCondition := p.Status.GetClusterCondition(v1alpha1.ClusterConditionRollback)
+						Ω(rollbackCondition.Status).Should(Equal(corev1.ConditionFalse))
+						_, errorCondition := p.Status.GetClusterCondition(v1alpha1.ClusterConditionError)
+						Ω(errorCondition.Status).Should(Equal(corev1.ConditionFalse))
+					})
+
+					It("should clear the target version", func() {
+						Ω(p.Status.TargetVersion).Should(Equal(""))
+					})
+
+					It("should close the history record", func() {
+						last := p.Status.VersionHistory[len(p.Status.VersionHistory)-1]
+						Ω(last.Outcome).Should(Equal(v1alpha1.TransitionCompleted))
+					})
+				})
+			})
+		})
 	})
 })
@@ -0,0 +1,150 @@
+/**
+ * Copyright (c) 2018 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravegacluster
+
+import (
+	"context"
+	"fmt"
+
+	pravegav1alpha1 "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/controller/pravega"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// syncRollback reverts a failed upgrade to the previously running version
+// with the same per-component orchestration as the upgrade itself. A
+// failed upgrade puts the spec back on the previous version but leaves
+// some components on the target one; this rolls them back, in reverse
+// order so the controller drops back before the segment stores it talks
+// to, and restores the config maps the target version may have rewritten
+func (r *ReconcilePravegaCluster) syncRollback(p *pravegav1alpha1.PravegaCluster) (err error) {
+	_, errorCondition := p.Status.GetClusterCondition(pravegav1alpha1.ClusterConditionError)
+	_, rollbackCondition := p.Status.GetClusterCondition(pravegav1alpha1.ClusterConditionRollback)
+
+	if rollbackCondition == nil || rollbackCondition.Status != corev1.ConditionTrue {
+		if errorCondition == nil || errorCondition.Status != corev1.ConditionTrue ||
+			errorCondition.Reason != "UpgradeFailed" {
+			return nil
+		}
+		if p.Status.PreviousVersion == "" || p.Spec.Version != p.Status.PreviousVersion {
+			// The user may also fix forward by moving the spec to another
+			// version; only a spec back on the previous version rolls back
+			return nil
+		}
+
+		// The upgraded components run with config maps generated for the
+		// target version; put the prior contents back before their pods
+		// restart on the old image. Restore before marking the rollback as
+		// triggered: if it fails the condition stays unset and the whole
+		// trigger, restore included, is retried on the next reconcile
+		if err = r.restoreConfigMaps(p); err != nil {
+			return err
+		}
+
+		defer func() {
+			r.client.Status().Update(context.TODO(), p)
+		}()
+
+		log.Printf("rolling cluster back to version %s", p.Status.PreviousVersion)
+		p.Status.TargetVersion = p.Status.PreviousVersion
+		p.Status.SetRollbackConditionTrue("RollbackTriggered",
+			fmt.Sprintf("rolling back to version %s", p.Status.PreviousVersion))
+
+		// The rollback moves away from the version the failed upgrade left
+		// parts of the cluster on, which the failed history entry records
+		fromVersion := p.Status.CurrentVersion
+		if n := len(p.Status.VersionHistory); n > 0 &&
+			p.Status.VersionHistory[n-1].Outcome == pravegav1alpha1.TransitionFailed {
+			fromVersion = p.Status.VersionHistory[n-1].ToVersion
+		}
+		p.Status.StartVersionTransition(fromVersion, p.Status.PreviousVersion)
+		return nil
+	}
+
+	defer func() {
+		r.client.Status().Update(context.TODO(), p)
+	}()
+
+	if p.IsUpgradePaused() {
+		log.Printf("rollback of cluster %s is paused", p.Name)
+		p.Status.UpdateProgress("RollbackPaused",
+			fmt.Sprintf("rollback paused by the %s annotation", pravegav1alpha1.UpgradePausedAnnotation))
+		return nil
+	}
+
+	var synced bool
+	for _, component := range []componentSyncVersionFun{
+		componentSyncVersionFun{
+			name: "controller",
+			fun:  r.syncControllerVersion,
+		},
+		componentSyncVersionFun{
+			name: "segmentstore",
+			fun:  r.syncSegmentStoreVersion,
+		},
+		componentSyncVersionFun{
+			name: "bookkeeper",
+			fun:  r.syncBookkeeperVersion,
+		},
+	} {
+		synced, err = component.fun(p)
+		if err != nil {
+			log.Printf("error rolling back cluster version, need manual intervention. %v", err)
+			p.Status.SetErrorConditionTrue("RollbackFailed", err.Error())
+			p.Status.SetRollbackConditionFalse()
+			p.Status.FinishVersionTransition(pravegav1alpha1.TransitionFailed, err.Error(), p.Status.Members.Unready)
+			p.Status.TargetVersion = ""
+			return nil
+		}
+
+		if !synced {
+			// Do not continue with the next component until this one is done
+			return nil
+		}
+		log.Printf("%s version rollback has been completed", component.name)
+	}
+
+	log.Printf("rollback to version %s completed", p.Status.PreviousVersion)
+	p.Status.TargetVersion = ""
+	p.Status.SetRollbackConditionFalse()
+	p.Status.SetErrorConditionFalse()
+	p.Status.FinishVersionTransition(pravegav1alpha1.TransitionCompleted, "", nil)
+	return nil
+}
+
+// restoreConfigMaps rewrites the component config maps from the reverted
+// spec, so rolled-back pods come up with the prior configuration
+func (r *ReconcilePravegaCluster) restoreConfigMaps(p *pravegav1alpha1.PravegaCluster) error {
+	for _, desired := range []*corev1.ConfigMap{
+		pravega.MakeControllerConfigMap(p),
+		pravega.MakeSegmentstoreConfigMap(p),
+		pravega.MakeBookieConfigMap(p),
+	} {
+		current := &corev1.ConfigMap{}
+		err := r.client.Get(context.TODO(),
+			types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		current.Data = desired.Data
+		if err := r.client.Update(context.TODO(), current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+/**
+ * Copyright (c) 2018 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package pravegacluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	pravegav1alpha1 "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// tlsCertHashAnnotation carries a digest of the TLS secrets on the pod
+// templates, so a renewed certificate rolls the pods through the regular
+// Deployment/StatefulSet update machinery
+const tlsCertHashAnnotation = "pravega.io/tls-cert-hash"
+
+// watchTLSSecrets enqueues the clusters referencing a Secret whenever it
+// changes, so certificate renewals are picked up right away instead of on
+// the next periodic resync
+func watchTLSSecrets(mgr manager.Manager, c controller.Controller) error {
+	mapFn := handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+		clusters := &pravegav1alpha1.PravegaClusterList{}
+		listOps := &client.ListOptions{Namespace: obj.Meta.GetNamespace()}
+		if err := mgr.GetClient().List(context.TODO(), listOps, clusters); err != nil {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, p := range clusters.Items {
+			if util.ContainsString(tlsSecretNames(&p), obj.Meta.GetName()) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      p.Name,
+						Namespace: p.Namespace,
+					},
+				})
+			}
+		}
+		return requests
+	})
+
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: mapFn})
+}
+
+// tlsSecretNames returns the names of the TLS secrets the cluster mounts
+func tlsSecretNames(p *pravegav1alpha1.PravegaCluster) []string {
+	if p.Spec.TLS == nil || p.Spec.TLS.Static == nil {
+		return nil
+	}
+
+	var names []string
+	for _, name := range []string{
+		p.Spec.TLS.Static.ControllerSecret,
+		p.Spec.TLS.Static.ControllerGrpcSecret,
+		p.Spec.TLS.Static.ControllerRestSecret,
+		p.Spec.TLS.Static.SegmentStoreSecret,
+	} {
+		if name != "" && !util.ContainsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// syncTLSCertificates rolls the pods when the content of the mounted TLS
+// secrets changes, e.g. after cert-manager renews a certificate. The segment
+// store rolls first, so its truststore already contains the renewed CA by
+// the time the controller starts presenting the new certificate. Without
+// this, renewed certs sit unused on disk until the pods happen to restart
+func (r *ReconcilePravegaCluster) syncTLSCertificates(p *pravegav1alpha1.PravegaCluster) (err error) {
+	names := tlsSecretNames(p)
+	if len(names) == 0 {
+		return nil
+	}
+
+	// An upgrade or rollback already rolls every pod with another
+	// version, do not interleave a second template change with it
+	for _, conditionType := range []pravegav1alpha1.ClusterConditionType{
+		pravegav1alpha1.ClusterConditionUpgrading,
+		pravegav1alpha1.ClusterConditionRollback,
+	} {
+		if _, condition := p.Status.GetClusterCondition(conditionType); condition != nil && condition.Status == corev1.ConditionTrue {
+			return nil
+		}
+	}
+
+	hash, err := r.tlsSecretsHash(p, names)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// A referenced secret does not exist yet, nothing to roll
+			return nil
+		}
+		return err
+	}
+
+	if p.Status.TlsCertHash == hash {
+		// The pods already rolled for these contents
+		return nil
+	}
+
+	if p.Status.TlsCertHash == "" {
+		// First observation: the pods have just been created, or an
+		// upgrade just rolled them with freshly generated templates. Either
+		// way they mounted the current secret contents on start, so adopt
+		// the hash without rolling anything
+		p.Status.TlsCertHash = hash
+		return nil
+	}
+
+	sts := &appsv1.StatefulSet{}
+	err = r.client.Get(context.TODO(),
+		types.NamespacedName{Name: util.StatefulSetNameForSegmentstore(p.Name), Namespace: p.Namespace}, sts)
+	if err != nil {
+		return err
+	}
+
+	if sts.Spec.Template.Annotations[tlsCertHashAnnotation] != hash {
+		if !p.InMaintenanceWindow() {
+			p.Status.QueueMaintenance("roll pods for changed TLS secrets")
+			return nil
+		}
+		if sts.Spec.Template.Annotations == nil {
+			sts.Spec.Template.Annotations = map[string]string{}
+		}
+		sts.Spec.Template.Annotations[tlsCertHashAnnotation] = hash
+		log.Printf("TLS secrets of cluster %s changed, rolling segment store pods", p.Name)
+		// The controller follows on a later reconcile, once the segment
+		// store has finished rolling
+		return r.client.Update(context.TODO(), sts)
+	}
+
+	if sts.Status.UpdatedReplicas != sts.Status.Replicas ||
+		sts.Status.ReadyReplicas != sts.Status.Replicas {
+		// Still rolling, hold off the controller
+		return nil
+	}
+
+	deploy := &appsv1.Deployment{}
+	err = r.client.Get(context.TODO(),
+		types.NamespacedName{Name: util.DeploymentNameForController(p.Name), Namespace: p.Namespace}, deploy)
+	if err != nil {
+		return err
+	}
+
+	if deploy.Spec.Template.Annotations[tlsCertHashAnnotation] != hash {
+		if deploy.Spec.Template.Annotations == nil {
+			deploy.Spec.Template.Annotations = map[string]string{}
+		}
+		deploy.Spec.Template.Annotations[tlsCertHashAnnotation] = hash
+		log.Printf("TLS secrets of cluster %s changed, rolling controller pods", p.Name)
+		return r.client.Update(context.TODO(), deploy)
+	}
+
+	if deploy.Status.UpdatedReplicas != deploy.Status.Replicas ||
+		deploy.Status.ReadyReplicas != deploy.Status.Replicas {
+		// Controller still rolling
+		return nil
+	}
+
+	// Both components run the current secret contents; record the hash so
+	// only the next real secret change rolls the pods again, even after an
+	// upgrade replaces the templates and drops the annotation
+	p.Status.TlsCertHash = hash
+	return nil
+}
+
+// tlsSecretsHash digests the content of the given secrets into a single
+// stable hash
+func (r *ReconcilePravegaCluster) tlsSecretsHash(p *pravegav1alpha1.PravegaCluster, names []string) (string, error) {
+	digest := sha256.New()
+	for _, name := range names {
+		secret := &corev1.Secret{}
+		err := r.client.Get(context.TODO(),
+			types.NamespacedName{Name: name, Namespace: p.Namespace}, secret)
+		if err != nil {
+			return "", err
+		}
+
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Fprintf(digest, "%s/%s:", name, key)
+			digest.Write(secret.Data[key])
+		}
+	}
+	return fmt.Sprintf("%x", digest.Sum(nil)), nil
+}
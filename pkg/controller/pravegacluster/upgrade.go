@@ -13,16 +13,21 @@ package pravegacluster
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	pravegav1alpha1 "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
 	"github.com/pravega/pravega-operator/pkg/controller/pravega"
 	"github.com/pravega/pravega-operator/pkg/util"
 	log "github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type componentSyncVersionFun struct {
@@ -51,18 +56,31 @@ func (r *ReconcilePravegaCluster) syncClusterVersion(p *pravegav1alpha1.PravegaC
 
 		if p.Status.TargetVersion == "" {
 			log.Println("syncing to an unknown version: cancelling upgrade process")
+			p.Status.FinishVersionTransition(pravegav1alpha1.TransitionCancelled, "target version cleared", nil)
 			return r.clearUpgradeStatus(p)
 		}
 
 		if p.Status.TargetVersion == p.Status.CurrentVersion {
 			log.Printf("syncing to version '%s' completed", p.Status.TargetVersion)
+			p.Status.FinishVersionTransition(pravegav1alpha1.TransitionCompleted, "", nil)
 			return r.clearUpgradeStatus(p)
 		}
 
+		if p.IsUpgradePaused() {
+			// Pods already handed to the Deployment/StatefulSet machinery
+			// finish, but no further template change or pod replacement
+			// happens until the annotation is removed
+			log.Printf("upgrade of cluster %s is paused", p.Name)
+			p.Status.UpdateProgress("UpgradePaused",
+				fmt.Sprintf("upgrade paused by the %s annotation", pravegav1alpha1.UpgradePausedAnnotation))
+			return nil
+		}
+
 		if err := r.syncComponentsVersion(p); err != nil {
 			log.Printf("error syncing cluster version, need manual intervention. %v", err)
 			// TODO: Trigger roll back to previous version
 			p.Status.SetErrorConditionTrue("UpgradeFailed", err.Error())
+			p.Status.FinishVersionTransition(pravegav1alpha1.TransitionFailed, err.Error(), p.Status.Members.Unready)
 			r.clearUpgradeStatus(p)
 		}
 		return nil
@@ -81,18 +99,88 @@ func (r *ReconcilePravegaCluster) syncClusterVersion(p *pravegav1alpha1.PravegaC
 		return nil
 	}
 
+	if !p.InMaintenanceWindow() {
+		log.Printf("upgrade to version %s is queued until the maintenance window opens", p.Spec.Version)
+		p.Status.QueueMaintenance(fmt.Sprintf("upgrade to version %s", p.Spec.Version))
+		return nil
+	}
+
+	if p.Spec.UpgradeSnapshot.IsEnabled() {
+		snapshotted, err := r.syncUpgradeSnapshot(p)
+		if err != nil {
+			return err
+		}
+		if !snapshotted {
+			// The upgrade only starts once the recovery point exists
+			return nil
+		}
+	}
+
 	// Need to sync cluster versions
 	log.Printf("syncing cluster version from %s to %s", p.Status.CurrentVersion, p.Spec.Version)
 
 	// Setting target version and condition.
 	// The upgrade process will start on the next reconciliation
+	p.Status.PreviousVersion = p.Status.CurrentVersion
 	p.Status.TargetVersion = p.Spec.Version
 	p.Status.SetUpgradingConditionTrue()
+	p.Status.StartVersionTransition(p.Status.CurrentVersion, p.Spec.Version)
 
 	return nil
 }
 
+// syncUpgradeSnapshot runs the pre-upgrade ZooKeeper snapshot Job and
+// reports whether it has completed. The artifact location is recorded in
+// the status once the Job succeeds; a failed Job blocks the upgrade
+func (r *ReconcilePravegaCluster) syncUpgradeSnapshot(p *pravegav1alpha1.PravegaCluster) (snapshotted bool, err error) {
+	job := &batchv1.Job{}
+	name := util.ZkSnapshotJobName(p.Name, p.Spec.Version)
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: p.Namespace}, job)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get snapshot job (%s): %v", name, err)
+		}
+
+		job = pravega.MakeZkSnapshotJob(p)
+		controllerutil.SetControllerReference(p, job, r.scheme)
+		log.Printf("creating pre-upgrade zookeeper snapshot job %s", job.Name)
+		if err = r.client.Create(context.TODO(), job); err != nil && !errors.IsAlreadyExists(err) {
+			return false, err
+		}
+		return false, nil
+	}
+
+	if job.Status.Succeeded > 0 {
+		p.Status.UpgradeSnapshotLocation = pravega.ZkSnapshotArtifact(p)
+		return true, nil
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return false, fmt.Errorf("pre-upgrade snapshot job (%s) failed: %s", job.Name, condition.Message)
+		}
+	}
+
+	log.Printf("waiting for pre-upgrade zookeeper snapshot job %s to complete", job.Name)
+	return false, nil
+}
+
 func (r *ReconcilePravegaCluster) clearUpgradeStatus(p *pravegav1alpha1.PravegaCluster) (err error) {
+	// The snapshot Job is removed with the upgrade so the next upgrade
+	// snapshots afresh; the artifact on the snapshot volume survives as
+	// the documented recovery point
+	if p.Spec.UpgradeSnapshot.IsEnabled() && p.Status.TargetVersion != "" {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      util.ZkSnapshotJobName(p.Name, p.Status.TargetVersion),
+				Namespace: p.Namespace,
+			},
+		}
+		if err = r.client.Delete(context.TODO(), job); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
 	p.Status.SetUpgradingConditionFalse()
 	p.Status.TargetVersion = ""
 	// need to deep copy the status struct, otherwise it will be overridden
@@ -132,6 +220,14 @@ func (r *ReconcilePravegaCluster) syncComponentsVersion(p *pravegav1alpha1.Prave
 
 		if synced {
 			log.Printf("%s version sync has been completed", component.name)
+
+			// Readiness alone does not prove the control plane still
+			// answers; hold the next component until the controller
+			// health endpoint does
+			if !r.controllerHealthy(p) {
+				log.Printf("controller health check failed after syncing %s, holding the upgrade", component.name)
+				return nil
+			}
 		} else {
 			// component version sync is still in progress
 			// Do not continue with the next component until this one is done
@@ -144,6 +240,29 @@ func (r *ReconcilePravegaCluster) syncComponentsVersion(p *pravegav1alpha1.Prave
 	return nil
 }
 
+// controllerHealthy probes the controller REST health endpoint. The
+// readiness probes only cover the individual pods; this verifies the
+// control plane as a whole still answers before the upgrade proceeds to
+// the next component. Clusters with a TLS-protected controller skip the
+// probe, the operator holds no client trust material for it
+func (r *ReconcilePravegaCluster) controllerHealthy(p *pravegav1alpha1.PravegaCluster) bool {
+	if p.Spec.TLS.IsSecureController() {
+		return true
+	}
+
+	url := fmt.Sprintf("http://%s.%s:%d/ping",
+		util.ServiceNameForController(p.Name), p.Namespace, util.ControllerRestPort(p))
+
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
 func (r *ReconcilePravegaCluster) syncControllerVersion(p *pravegav1alpha1.PravegaCluster) (synced bool, err error) {
 	deploy := &appsv1.Deployment{}
 	name := util.DeploymentNameForController(p.Name)
@@ -152,7 +271,7 @@ func (r *ReconcilePravegaCluster) syncControllerVersion(p *pravegav1alpha1.Prave
 		return false, fmt.Errorf("failed to get deployment (%s): %v", deploy.Name, err)
 	}
 
-	targetImage, err := util.PravegaTargetImage(p)
+	targetImage, err := util.PravegaControllerTargetImage(p)
 	if err != nil {
 		return false, err
 	}
@@ -179,6 +298,8 @@ func (r *ReconcilePravegaCluster) syncControllerVersion(p *pravegav1alpha1.Prave
 	if deploy.Status.UpdatedReplicas != deploy.Status.Replicas ||
 		deploy.Status.UpdatedReplicas != deploy.Status.ReadyReplicas {
 		// Update still in progress
+		p.Status.UpdateProgress("SyncingController",
+			fmt.Sprintf("%d out of %d controller pods updated", deploy.Status.UpdatedReplicas, deploy.Status.Replicas))
 		return false, nil
 	}
 
@@ -195,17 +316,32 @@ func (r *ReconcilePravegaCluster) syncSegmentStoreVersion(p *pravegav1alpha1.Pra
 		return false, fmt.Errorf("failed to get statefulset (%s): %v", sts.Name, err)
 	}
 
-	targetImage, err := util.PravegaTargetImage(p)
+	targetImage, err := util.PravegaSegmentStoreTargetImage(p)
 	if err != nil {
 		return false, err
 	}
 
+	canary := r.canaryApplies(p, sts)
+
 	if sts.Spec.Template.Spec.Containers[0].Image != targetImage {
 		// Need to update pod template
 		// This will trigger the rolling upgrade process
 		log.Printf("updating statefulset (%s) template image to '%s'", sts.Name, targetImage)
 
 		sts.Spec.Template = pravega.MakeSegmentStorePodTemplate(p)
+		if canary {
+			// Hold every pod but the highest ordinal back, so a single
+			// canary soaks on the new version before the rest follow
+			partition := *sts.Spec.Replicas - 1
+			sts.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition: &partition,
+			}
+		} else {
+			// A rollback or a canary left over from a failed attempt must
+			// not hold any pod back
+			sts.Spec.UpdateStrategy.RollingUpdate = nil
+		}
+		delete(sts.Annotations, canarySoakStartAnnotation)
 		err = r.client.Update(context.TODO(), sts)
 		if err != nil {
 			return false, err
@@ -216,6 +352,17 @@ func (r *ReconcilePravegaCluster) syncSegmentStoreVersion(p *pravegav1alpha1.Pra
 
 	// Pod template already updated
 
+	if canary && sts.Spec.UpdateStrategy.RollingUpdate != nil &&
+		sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil &&
+		*sts.Spec.UpdateStrategy.RollingUpdate.Partition > 0 {
+		if err = r.syncSegmentStoreCanary(p, sts); err != nil {
+			return false, err
+		}
+		// Released or still soaking; either way the remaining pods have
+		// not finished rolling yet
+		return false, nil
+	}
+
 	log.Printf("statefulset (%s) status: %d updated, %d ready, %d target", sts.Name,
 		sts.Status.UpdatedReplicas, sts.Status.ReadyReplicas, sts.Status.Replicas)
 
@@ -223,6 +370,8 @@ func (r *ReconcilePravegaCluster) syncSegmentStoreVersion(p *pravegav1alpha1.Pra
 	if sts.Status.UpdatedReplicas != sts.Status.Replicas ||
 		sts.Status.UpdatedReplicas != sts.Status.ReadyReplicas {
 		// Upgrade still in progress
+		p.Status.UpdateProgress("SyncingSegmentstore",
+			fmt.Sprintf("%d out of %d segment store pods updated", sts.Status.UpdatedReplicas, sts.Status.Replicas))
 		return false, nil
 	}
 
@@ -230,6 +379,75 @@ func (r *ReconcilePravegaCluster) syncSegmentStoreVersion(p *pravegav1alpha1.Pra
 	return true, nil
 }
 
+// canarySoakStartAnnotation records on the segment store stateful set when
+// the canary pod became ready on the target version, so the soak period
+// survives operator restarts
+const canarySoakStartAnnotation = "pravega.io/canary-soak-start"
+
+// canaryApplies returns whether the segment store upgrade goes through a
+// canary phase. A rollback skips it: the previous version already proved
+// itself and should be restored as fast as possible
+func (r *ReconcilePravegaCluster) canaryApplies(p *pravegav1alpha1.PravegaCluster, sts *appsv1.StatefulSet) bool {
+	if p.Spec.Pravega == nil || !p.Spec.Pravega.SegmentStoreCanaryUpgrade.IsEnabled() {
+		return false
+	}
+	if sts.Spec.Replicas == nil || *sts.Spec.Replicas < 2 {
+		// With a single pod there is nothing to hold back
+		return false
+	}
+	_, rollbackCondition := p.Status.GetClusterCondition(pravegav1alpha1.ClusterConditionRollback)
+	return rollbackCondition == nil || rollbackCondition.Status != corev1.ConditionTrue
+}
+
+// syncSegmentStoreCanary drives the canary phase: wait for the canary pod
+// to come up healthy on the target version, soak it for the configured
+// period, and only then release the partition holding the other pods back.
+// A canary that restarts fails the upgrade instead of releasing it
+func (r *ReconcilePravegaCluster) syncSegmentStoreCanary(p *pravegav1alpha1.PravegaCluster, sts *appsv1.StatefulSet) error {
+	ready, err := r.checkUpdatedPods(sts, p.Status.TargetVersion)
+	if err != nil {
+		return fmt.Errorf("canary check failed: %v", err)
+	}
+
+	if sts.Status.UpdatedReplicas < 1 || !ready {
+		p.Status.UpdateProgress("SyncingSegmentstore",
+			"waiting for the canary segment store pod to become ready on the new version")
+		return nil
+	}
+
+	start, err := time.Parse(time.RFC3339, sts.Annotations[canarySoakStartAnnotation])
+	if err != nil {
+		// Canary just became ready, start the soak clock
+		if sts.Annotations == nil {
+			sts.Annotations = map[string]string{}
+		}
+		sts.Annotations[canarySoakStartAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		log.Printf("canary segment store pod of cluster %s is ready, soaking for %s",
+			p.Name, p.Spec.Pravega.SegmentStoreCanaryUpgrade.SoakTime())
+		return r.client.Update(context.TODO(), sts)
+	}
+
+	soakTime := p.Spec.Pravega.SegmentStoreCanaryUpgrade.SoakTime()
+	if soaked := time.Since(start); soaked < soakTime {
+		p.Status.UpdateProgress("SyncingSegmentstore",
+			fmt.Sprintf("canary segment store pod soaking, %s of %s elapsed",
+				soaked.Truncate(time.Second), soakTime))
+		return nil
+	}
+
+	// The canary stayed healthy through the soak; verify the control plane
+	// still answers before exposing the remaining pods to the new version
+	if !r.controllerHealthy(p) {
+		log.Printf("controller health check failed during the canary soak, holding the upgrade")
+		return nil
+	}
+
+	log.Printf("canary soak of cluster %s completed, releasing the remaining segment store pods", p.Name)
+	sts.Spec.UpdateStrategy.RollingUpdate = nil
+	delete(sts.Annotations, canarySoakStartAnnotation)
+	return r.client.Update(context.TODO(), sts)
+}
+
 func (r *ReconcilePravegaCluster) syncBookkeeperVersion(p *pravegav1alpha1.PravegaCluster) (synced bool, err error) {
 	sts := &appsv1.StatefulSet{}
 	name := util.StatefulSetNameForBookie(p.Name)
@@ -273,6 +491,9 @@ func (r *ReconcilePravegaCluster) syncBookkeeperVersion(p *pravegav1alpha1.Prave
 	// Upgrade still in progress
 	// If all replicas are ready, upgrade an old pod
 
+	p.Status.UpdateProgress("SyncingBookkeeper",
+		fmt.Sprintf("%d out of %d bookie pods updated", sts.Status.UpdatedReplicas, sts.Status.Replicas))
+
 	ready, err := r.checkUpdatedPods(sts, p.Status.TargetVersion)
 	if err != nil {
 		// Abort if there is any errors with the updated pods
@@ -12,6 +12,9 @@ package util
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
@@ -20,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -76,6 +80,72 @@ func PodAntiAffinity(component string, clusterName string) *corev1.Affinity {
 	}
 }
 
+// ConfigMapHash returns a stable hash of the config map data. The hash is
+// stamped on pod templates so workloads roll when their configuration
+// changes.
+func ConfigMapHash(data map[string]string) string {
+	// json.Marshal sorts map keys, making the encoding deterministic
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		// A map of strings always encodes; this should never happen
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(encoded))
+}
+
+// ApplyProbeOverrides replaces the timing parameters of the given probe with
+// the non-zero values of the provided spec. A nil spec leaves the probe
+// untouched.
+func ApplyProbeOverrides(probe *corev1.Probe, spec *v1alpha1.ProbeSpec) {
+	if probe == nil || spec == nil {
+		return
+	}
+	if spec.InitialDelaySeconds != 0 {
+		probe.InitialDelaySeconds = spec.InitialDelaySeconds
+	}
+	if spec.PeriodSeconds != 0 {
+		probe.PeriodSeconds = spec.PeriodSeconds
+	}
+	if spec.TimeoutSeconds != 0 {
+		probe.TimeoutSeconds = spec.TimeoutSeconds
+	}
+	if spec.SuccessThreshold != 0 {
+		probe.SuccessThreshold = spec.SuccessThreshold
+	}
+	if spec.FailureThreshold != 0 {
+		probe.FailureThreshold = spec.FailureThreshold
+	}
+}
+
+// ApplyPodTemplateOverride strategically merges the given partial pod
+// template over the generated one, letting users set pod fields that the
+// cluster spec does not model. A nil override leaves the template untouched.
+func ApplyPodTemplateOverride(template *corev1.PodTemplateSpec, override *corev1.PodTemplateSpec) error {
+	if override == nil {
+		return nil
+	}
+
+	original, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pod template: %v", err)
+	}
+
+	patch, err := json.Marshal(override)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pod template override: %v", err)
+	}
+
+	merged, err := strategicpatch.StrategicMergePatch(original, patch, corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf("failed to merge pod template override: %v", err)
+	}
+
+	if err = json.Unmarshal(merged, template); err != nil {
+		return fmt.Errorf("failed to unmarshal merged pod template: %v", err)
+	}
+	return nil
+}
+
 // Wait for pods in cluster to be terminated
 func WaitForClusterToTerminate(kubeClient client.Client, p *v1alpha1.PravegaCluster) (err error) {
 	listOptions := &client.ListOptions{
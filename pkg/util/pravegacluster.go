@@ -45,6 +45,22 @@ func StatefulSetNameForBookie(clusterName string) string {
 	return fmt.Sprintf("%s-bookie", clusterName)
 }
 
+func RoleNameForCluster(clusterName string) string {
+	return fmt.Sprintf("%s-pravega", clusterName)
+}
+
+func RoleBindingNameForCluster(clusterName string) string {
+	return fmt.Sprintf("%s-pravega", clusterName)
+}
+
+func SecurityPolicyNameForCluster(clusterName string) string {
+	return fmt.Sprintf("%s-pravega", clusterName)
+}
+
+func PolicyRoleNameForCluster(clusterName string) string {
+	return fmt.Sprintf("%s-pravega-policy", clusterName)
+}
+
 func PdbNameForController(clusterName string) string {
 	return fmt.Sprintf("%s-pravega-controller", clusterName)
 }
@@ -53,14 +69,70 @@ func ConfigMapNameForController(clusterName string) string {
 	return fmt.Sprintf("%s-pravega-controller", clusterName)
 }
 
+func AuditLogbackConfigMapNameForController(clusterName string) string {
+	return fmt.Sprintf("%s-pravega-controller-audit-logback", clusterName)
+}
+
+func ZkSnapshotJobName(clusterName string, version string) string {
+	return fmt.Sprintf("%s-zk-snapshot-%s", clusterName, strings.Replace(version, ".", "-", -1))
+}
+
 func ServiceNameForController(clusterName string) string {
 	return fmt.Sprintf("%s-pravega-controller", clusterName)
 }
 
+func IngressNameForController(clusterName string) string {
+	return fmt.Sprintf("%s-pravega-controller", clusterName)
+}
+
+func SecretNameForController(clusterName string) string {
+	return fmt.Sprintf("%s-pravega-controller", clusterName)
+}
+
+// ControllerRestPort returns the configured controller REST port, falling
+// back to the standard 10080
+func ControllerRestPort(p *v1alpha1.PravegaCluster) int32 {
+	if p.Spec.Pravega != nil && p.Spec.Pravega.ControllerRestPort != 0 {
+		return p.Spec.Pravega.ControllerRestPort
+	}
+	return 10080
+}
+
+// ControllerGrpcPort returns the configured controller gRPC port, falling
+// back to the standard 9090
+func ControllerGrpcPort(p *v1alpha1.PravegaCluster) int32 {
+	if p.Spec.Pravega != nil && p.Spec.Pravega.ControllerGrpcPort != 0 {
+		return p.Spec.Pravega.ControllerGrpcPort
+	}
+	return 9090
+}
+
+// ExternalDNSHostname builds the hostname advertised to external-dns for a
+// service, trimming any trailing dot from the configured domain
+func ExternalDNSHostname(serviceName string, domainName string) string {
+	return fmt.Sprintf("%s.%s", serviceName, strings.TrimSuffix(domainName, "."))
+}
+
 func ServiceNameForSegmentStore(clusterName string, index int32) string {
 	return fmt.Sprintf("%s-pravega-segmentstore-%d", clusterName, index)
 }
 
+func HeadlessServiceNameForController(clusterName string) string {
+	return fmt.Sprintf("%s-pravega-controller-headless", clusterName)
+}
+
+func AdminServiceNameForSegmentStore(clusterName string) string {
+	return fmt.Sprintf("%s-pravega-segmentstore-admin", clusterName)
+}
+
+func DebugServiceNameForController(clusterName string) string {
+	return fmt.Sprintf("%s-pravega-controller-debug", clusterName)
+}
+
+func DebugServiceNameForSegmentStore(clusterName string) string {
+	return fmt.Sprintf("%s-pravega-segmentstore-debug", clusterName)
+}
+
 func HeadlessServiceNameForSegmentStore(clusterName string) string {
 	return fmt.Sprintf("%s-pravega-segmentstore-headless", clusterName)
 }
@@ -110,6 +182,18 @@ func LabelsForPravegaCluster(pravegaCluster *v1alpha1.PravegaCluster) map[string
 	}
 }
 
+// MergeMaps merges the provided maps from left to right. Values from later
+// maps take precedence over values from earlier ones.
+func MergeMaps(maps ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
 func PvcIsOrphan(stsPvcName string, replicas int32) bool {
 	index := strings.LastIndexAny(stsPvcName, "-")
 	if index == -1 {
@@ -125,13 +209,21 @@ func PvcIsOrphan(stsPvcName string, replicas int32) bool {
 }
 
 func PravegaControllerServiceURL(pravegaCluster v1alpha1.PravegaCluster) string {
-	return fmt.Sprintf("tcp://%v.%v:%v", ServiceNameForController(pravegaCluster.Name), pravegaCluster.Namespace, "9090")
+	return fmt.Sprintf("tcp://%v.%v:%v", ServiceNameForController(pravegaCluster.Name), pravegaCluster.Namespace, ControllerGrpcPort(&pravegaCluster))
 }
 
 func HealthcheckCommand(port int32) []string {
 	return []string{"/bin/sh", "-c", fmt.Sprintf("netstat -ltn 2> /dev/null | grep %d || ss -ltn 2> /dev/null | grep %d", port, port)}
 }
 
+// DrainCommand waits until there are no established client connections left
+// on the given port. It is used as a preStop hook so that a pod keeps serving
+// in-flight requests until clients have moved away or the termination grace
+// period expires.
+func DrainCommand(port int32) []string {
+	return []string{"/bin/sh", "-c", fmt.Sprintf("while netstat -tn 2> /dev/null | grep ESTABLISHED | grep %d || ss -tn 2> /dev/null | grep ESTAB | grep %d; do sleep 1; done", port, port)}
+}
+
 // Min returns the smaller of x or y.
 func Min(x, y int32) int32 {
 	if x > y {
@@ -163,26 +255,96 @@ func GetClusterExpectedSize(p *v1alpha1.PravegaCluster) (size int) {
 	return int(p.Spec.Pravega.ControllerReplicas + p.Spec.Pravega.SegmentStoreReplicas + p.Spec.Bookkeeper.Replicas)
 }
 
+// imageRef builds an image reference, pinned by digest when one is
+// configured and by the version tag otherwise
+func imageRef(repository string, version string, digest string) string {
+	if digest != "" {
+		return fmt.Sprintf("%s@%s", repository, digest)
+	}
+	return fmt.Sprintf("%s:%s", repository, version)
+}
+
 func PravegaImage(p *v1alpha1.PravegaCluster) (image string) {
-	return fmt.Sprintf("%s:%s", p.Spec.Pravega.Image.Repository, p.Spec.Version)
+	return imageRef(p.Spec.Pravega.Image.Repository, p.Spec.Version, p.Spec.Pravega.Image.Digest)
+}
+
+// PravegaControllerImage is the image used by controller pods. It is the
+// common Pravega image unless a controller-specific repository is configured.
+func PravegaControllerImage(p *v1alpha1.PravegaCluster) (image string) {
+	return imageRef(pravegaControllerRepository(p), p.Spec.Version, pravegaControllerDigest(p))
+}
+
+// PravegaSegmentStoreImage is the image used by segment store pods. It is the
+// common Pravega image unless a segment store-specific repository is configured.
+func PravegaSegmentStoreImage(p *v1alpha1.PravegaCluster) (image string) {
+	return imageRef(pravegaSegmentStoreRepository(p), p.Spec.Version, pravegaSegmentStoreDigest(p))
+}
+
+func PravegaControllerImagePullPolicy(p *v1alpha1.PravegaCluster) v1.PullPolicy {
+	if p.Spec.Pravega.ControllerImage != nil && p.Spec.Pravega.ControllerImage.PullPolicy != "" {
+		return p.Spec.Pravega.ControllerImage.PullPolicy
+	}
+	return p.Spec.Pravega.Image.PullPolicy
+}
+
+func PravegaSegmentStoreImagePullPolicy(p *v1alpha1.PravegaCluster) v1.PullPolicy {
+	if p.Spec.Pravega.SegmentStoreImage != nil && p.Spec.Pravega.SegmentStoreImage.PullPolicy != "" {
+		return p.Spec.Pravega.SegmentStoreImage.PullPolicy
+	}
+	return p.Spec.Pravega.Image.PullPolicy
+}
+
+func pravegaControllerRepository(p *v1alpha1.PravegaCluster) string {
+	if p.Spec.Pravega.ControllerImage != nil && p.Spec.Pravega.ControllerImage.Repository != "" {
+		return p.Spec.Pravega.ControllerImage.Repository
+	}
+	return p.Spec.Pravega.Image.Repository
+}
+
+func pravegaSegmentStoreRepository(p *v1alpha1.PravegaCluster) string {
+	if p.Spec.Pravega.SegmentStoreImage != nil && p.Spec.Pravega.SegmentStoreImage.Repository != "" {
+		return p.Spec.Pravega.SegmentStoreImage.Repository
+	}
+	return p.Spec.Pravega.Image.Repository
+}
+
+func pravegaControllerDigest(p *v1alpha1.PravegaCluster) string {
+	if p.Spec.Pravega.ControllerImage != nil && p.Spec.Pravega.ControllerImage.Digest != "" {
+		return p.Spec.Pravega.ControllerImage.Digest
+	}
+	return p.Spec.Pravega.Image.Digest
+}
+
+func pravegaSegmentStoreDigest(p *v1alpha1.PravegaCluster) string {
+	if p.Spec.Pravega.SegmentStoreImage != nil && p.Spec.Pravega.SegmentStoreImage.Digest != "" {
+		return p.Spec.Pravega.SegmentStoreImage.Digest
+	}
+	return p.Spec.Pravega.Image.Digest
 }
 
 func BookkeeperImage(p *v1alpha1.PravegaCluster) (image string) {
-	return fmt.Sprintf("%s:%s", p.Spec.Bookkeeper.Image.Repository, p.Spec.Version)
+	return imageRef(p.Spec.Bookkeeper.Image.Repository, p.Spec.Version, p.Spec.Bookkeeper.Image.Digest)
+}
+
+func PravegaControllerTargetImage(p *v1alpha1.PravegaCluster) (string, error) {
+	if p.Status.TargetVersion == "" {
+		return "", fmt.Errorf("target version is not set")
+	}
+	return imageRef(pravegaControllerRepository(p), p.Status.TargetVersion, pravegaControllerDigest(p)), nil
 }
 
-func PravegaTargetImage(p *v1alpha1.PravegaCluster) (string, error) {
+func PravegaSegmentStoreTargetImage(p *v1alpha1.PravegaCluster) (string, error) {
 	if p.Status.TargetVersion == "" {
 		return "", fmt.Errorf("target version is not set")
 	}
-	return fmt.Sprintf("%s:%s", p.Spec.Pravega.Image.Repository, p.Status.TargetVersion), nil
+	return imageRef(pravegaSegmentStoreRepository(p), p.Status.TargetVersion, pravegaSegmentStoreDigest(p)), nil
 }
 
 func BookkeeperTargetImage(p *v1alpha1.PravegaCluster) (string, error) {
 	if p.Status.TargetVersion == "" {
 		return "", fmt.Errorf("target version is not set")
 	}
-	return fmt.Sprintf("%s:%s", p.Spec.Bookkeeper.Image.Repository, p.Status.TargetVersion), nil
+	return imageRef(p.Spec.Bookkeeper.Image.Repository, p.Status.TargetVersion, p.Spec.Bookkeeper.Image.Digest), nil
 }
 
 func GetPodVersion(pod *v1.Pod) string {
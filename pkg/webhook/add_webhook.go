@@ -21,10 +21,19 @@ import (
 
 	pravegav1alpha1 "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
 	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	apitypes "k8s.io/apimachinery/pkg/types"
 )
 
 const (
-	CertDir = "/tmp"
+	// CertDir is where the deployment mounts CertSecretName. The server
+	// serves with the key and certificate it finds there
+	CertDir = "/tmp/cert"
+
+	// CertSecretName is the secret the operator provisions its self-signed
+	// serving certificate into. Keeping it in a secret instead of the pod
+	// filesystem means the CA survives operator restarts, so the webhook
+	// configurations do not go stale whenever the pod moves
+	CertSecretName = "pravega-webhook-cert"
 )
 
 // AddToManagerFuncs is a list of functions to add all Webhooks to the Manager
@@ -74,13 +83,24 @@ func newValidatingWebhook(mgr manager.Manager) (*admission.Webhook, error) {
 		Build()
 }
 
+// newWebhookServer builds a server that manages its own serving
+// certificate: the bootstrap provisions a self-signed certificate into
+// CertSecretName, patches the resulting CA bundle into the webhook
+// configuration and replaces the certificate before it expires, so
+// installs do not depend on cert-manager
 func newWebhookServer(mgr manager.Manager) (*webhook.Server, error) {
+	namespace := os.Getenv("WATCH_NAMESPACE")
 	return webhook.NewServer("pravega-admission-webhook", mgr, webhook.ServerOptions{
 		CertDir: CertDir,
 		BootstrapOptions: &webhook.BootstrapOptions{
+			MutatingWebhookConfigName: "pravega-webhook-config",
+			Secret: &apitypes.NamespacedName{
+				Namespace: namespace,
+				Name:      CertSecretName,
+			},
 			// TODO: garbage collect webhook k8s service
 			Service: &webhook.Service{
-				Namespace: os.Getenv("WATCH_NAMESPACE"),
+				Namespace: namespace,
 				Name:      "pravega-admission-webhook",
 				Selectors: map[string]string{
 					"component": "pravega-operator",
@@ -0,0 +1,138 @@
+/**
+ * Copyright (c) 2019 Dell Inc., or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ */
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pravegav1alpha1 "github.com/pravega/pravega-operator/pkg/apis/pravega/v1alpha1"
+	"github.com/pravega/pravega-operator/pkg/util"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// validOptionPrefixes lists the property namespaces accepted in
+	// spec.pravega.options. The catalog is intentionally coarse: it
+	// catches namespace typos without chasing every per-version property.
+	validOptionPrefixes = []string{
+		"attributeIndex.",
+		"autoScale.",
+		"bookkeeper.",
+		"controller.",
+		"durableLog.",
+		"extendeds3.",
+		"filesystem.",
+		"hdfs.",
+		"log.",
+		"metrics.",
+		"pravega.",
+		"pravegaservice.",
+		"readIndex.",
+		"rocksdb.",
+		"storage.",
+		"writer.",
+	}
+
+	// deprecatedOptions maps properties to their replacement from the
+	// version they were renamed in. Extend this table whenever a Pravega
+	// release renames a property
+	deprecatedOptions = map[string]deprecatedOption{
+		"autoScale.muteInSeconds": {since: "0.5.0", replacement: "autoScale.cooldownInSeconds"},
+	}
+)
+
+type deprecatedOption struct {
+	since       string
+	replacement string
+}
+
+// migrateOptions rewrites deprecated option names to their renamed
+// counterparts once the target version uses the new name, so a version
+// upgrade does not silently drop configuration. The rewrites are noted on
+// the OptionRewritesAnnotation — the webhook cannot write the status
+// subresource — and the reconciler reports them in the cluster status
+func (pwh *pravegaWebhookHandler) migrateOptions(ctx context.Context, p *pravegav1alpha1.PravegaCluster) error {
+	if p.Spec.Pravega == nil {
+		return nil
+	}
+	for _, options := range []map[string]string{p.Spec.Pravega.Options, p.Spec.Pravega.ControllerOptions, p.Spec.Pravega.SegmentStoreOptions} {
+		for name, value := range options {
+			deprecated, ok := deprecatedOptions[name]
+			if !ok {
+				continue
+			}
+			renamed, _ := util.CompareVersions(p.Spec.Version, deprecated.since, ">=")
+			if !renamed {
+				continue
+			}
+			// An explicit value under the new name wins over the
+			// migrated one
+			if _, ok := options[deprecated.replacement]; !ok {
+				options[deprecated.replacement] = value
+			}
+			delete(options, name)
+
+			recordOptionRewrite(p, fmt.Sprintf("%s -> %s", name, deprecated.replacement))
+			log.Printf("migrated Pravega option %q to %q for version %s", name, deprecated.replacement, p.Spec.Version)
+		}
+	}
+	return nil
+}
+
+// recordOptionRewrite appends the rewrite to the OptionRewritesAnnotation
+// unless it is already listed
+func recordOptionRewrite(p *pravegav1alpha1.PravegaCluster, rewrite string) {
+	existing := strings.Split(p.Annotations[pravegav1alpha1.OptionRewritesAnnotation], ",")
+	if util.ContainsString(existing, rewrite) {
+		return
+	}
+	if p.Annotations == nil {
+		p.Annotations = map[string]string{}
+	}
+	if p.Annotations[pravegav1alpha1.OptionRewritesAnnotation] == "" {
+		p.Annotations[pravegav1alpha1.OptionRewritesAnnotation] = rewrite
+		return
+	}
+	p.Annotations[pravegav1alpha1.OptionRewritesAnnotation] += "," + rewrite
+}
+
+// validateOptions checks the configured Pravega options against the option
+// catalog, so a typo surfaces as an admission error instead of a cluster
+// that silently ignores the setting.
+func (pwh *pravegaWebhookHandler) validateOptions(ctx context.Context, p *pravegav1alpha1.PravegaCluster) error {
+	if p.Spec.Pravega == nil {
+		return nil
+	}
+	for _, options := range []map[string]string{p.Spec.Pravega.Options, p.Spec.Pravega.ControllerOptions, p.Spec.Pravega.SegmentStoreOptions} {
+		for name := range options {
+			if !hasValidOptionPrefix(name) {
+				return fmt.Errorf("unknown Pravega option %q: unrecognized property namespace", name)
+			}
+			if deprecated, ok := deprecatedOptions[name]; ok {
+				if renamed, _ := util.CompareVersions(p.Spec.Version, deprecated.since, ">="); renamed {
+					return fmt.Errorf("Pravega option %q was renamed to %q in version %s", name, deprecated.replacement, deprecated.since)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasValidOptionPrefix(name string) bool {
+	for _, prefix := range validOptionPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -14,6 +14,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -42,6 +45,23 @@ var (
 		"0.4.0": []string{"0.4.0"},
 		"0.5.0": []string{"0.5.0"},
 	}
+
+	// metadataFormats records the metadata format generation each supported
+	// version writes to ZooKeeper and tier 2. A downgrade is only safe
+	// between versions of the same generation: older software cannot read
+	// metadata written in a newer format
+	metadataFormats = map[string]int{
+		"0.1.0": 1,
+		"0.2.0": 2,
+		"0.3.0": 3,
+		"0.3.1": 3,
+		"0.3.2": 3,
+		"0.4.0": 4,
+		"0.5.0": 5,
+	}
+
+	// imageDigestRegexp matches a content digest image pin
+	imageDigestRegexp = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
 )
 
 type pravegaWebhookHandler struct {
@@ -78,10 +98,343 @@ func (pwh *pravegaWebhookHandler) mutatePravegaManifest(ctx context.Context, p *
 		return err
 	}
 
+	if err := pwh.validateJvmOptions(ctx, p); err != nil {
+		return err
+	}
+
+	if err := pwh.migrateOptions(ctx, p); err != nil {
+		return err
+	}
+
+	if err := pwh.validateOptions(ctx, p); err != nil {
+		return err
+	}
+
+	if err := pwh.validateAuthentication(ctx, p); err != nil {
+		return err
+	}
+
+	if err := pwh.validateSegmentContainerCount(ctx, p); err != nil {
+		return err
+	}
+
+	if err := pwh.validateTLSSecrets(ctx, p); err != nil {
+		return err
+	}
+
+	if err := validatePodTemplateOverrides(p); err != nil {
+		return err
+	}
+
+	if p.Spec.Profile != "" && !pravegav1alpha1.IsValidProfile(p.Spec.Profile) {
+		return fmt.Errorf("unknown cluster profile %q: options are \"small\", \"medium\" and \"large\"", p.Spec.Profile)
+	}
+
+	if p.Spec.Pravega != nil {
+		switch p.Spec.Pravega.GcProfile {
+		case "", "default", "g1", "zgc":
+		default:
+			return fmt.Errorf("unknown GC profile %q: options are \"g1\", \"zgc\" and \"default\"", p.Spec.Pravega.GcProfile)
+		}
+	}
+
+	if p.Spec.TLS != nil && p.Spec.TLS.MinVersion != "" && !pravegav1alpha1.IsValidTLSProtocolVersion(p.Spec.TLS.MinVersion) {
+		return fmt.Errorf("unknown TLS protocol version %q: options are \"TLSv1.1\", \"TLSv1.2\" and \"TLSv1.3\"", p.Spec.TLS.MinVersion)
+	}
+
+	if p.Spec.SecurityPolicy != nil && !p.Spec.SecurityPolicy.IsEnabled() {
+		return fmt.Errorf("unknown security policy type %q: options are \"scc\" and \"psp\"", p.Spec.SecurityPolicy.Type)
+	}
+
+	if p.Spec.Zookeeper != nil && p.Spec.Zookeeper.Auth != nil {
+		auth := p.Spec.Zookeeper.Auth
+		if auth.Scheme != "digest" && auth.Scheme != "sasl" {
+			return fmt.Errorf("unknown zookeeper auth scheme %q: options are \"digest\" and \"sasl\"", auth.Scheme)
+		}
+		if auth.CredentialsSecret == "" {
+			return fmt.Errorf("spec.zookeeper.auth requires a credentialsSecret")
+		}
+	}
+
+	if err := validateImageDigests(p); err != nil {
+		return err
+	}
+
+	if p.Spec.Pravega != nil && p.Spec.Pravega.ControllerAuditLog.IsEnabled() {
+		if p.Spec.Pravega.ControllerLogbackConfigMap != "" {
+			return fmt.Errorf("controllerAuditLog cannot be combined with controllerLogbackConfigMap: add the audit appender to the custom logback configuration instead")
+		}
+		switch p.Spec.Pravega.ControllerAuditLog.Format {
+		case "", "json", "text":
+		default:
+			return fmt.Errorf("unknown audit log format %q: options are \"json\" and \"text\"", p.Spec.Pravega.ControllerAuditLog.Format)
+		}
+	}
+
+	if p.Spec.UpgradeSnapshot.IsEnabled() && p.Spec.UpgradeSnapshot.Image == "" {
+		return fmt.Errorf("upgradeSnapshot requires an image to run the snapshot job")
+	}
+
+	if p.Spec.MaintenanceWindow != nil {
+		mw := p.Spec.MaintenanceWindow
+		if _, err := mw.Contains(time.Now()); err != nil {
+			return err
+		}
+		if mw.DurationMinutes < 1 {
+			return fmt.Errorf("maintenance window duration must be at least one minute")
+		}
+		for _, day := range mw.DaysOfWeek {
+			if !pravegav1alpha1.IsValidDayOfWeek(day) {
+				return fmt.Errorf("unknown day of week %q in the maintenance window", day)
+			}
+		}
+	}
+
+	if p.Spec.Pravega != nil && p.Spec.Pravega.SegmentStoreCanaryUpgrade != nil &&
+		p.Spec.Pravega.SegmentStoreCanaryUpgrade.SoakTimeSeconds < 0 {
+		return fmt.Errorf("canary soak time must not be negative")
+	}
+
+	if p.Spec.Pravega != nil && p.Spec.Pravega.Tier2 != nil && p.Spec.Pravega.Tier2.Ecs != nil {
+		ecs := p.Spec.Pravega.Tier2.Ecs
+		switch ecs.ServerSideEncryption {
+		case "", "sse-s3":
+		case "sse-kms":
+			if ecs.KmsKeyId == "" {
+				return fmt.Errorf("tier 2 encryption \"sse-kms\" requires a kmsKeyId")
+			}
+		default:
+			return fmt.Errorf("unknown tier 2 encryption %q: options are \"sse-s3\" and \"sse-kms\"", ecs.ServerSideEncryption)
+		}
+	}
+
 	//Add other validators here
 	return nil
 }
 
+// validatePodTemplateOverrides dry-runs the pod template overrides against
+// an empty template, so a merge that cannot apply is rejected on admission
+// instead of being dropped with only an operator log line when the pods are
+// generated
+func validatePodTemplateOverrides(p *pravegav1alpha1.PravegaCluster) error {
+	if p.Spec.Pravega == nil {
+		return nil
+	}
+	for name, override := range map[string]*corev1.PodTemplateSpec{
+		"controllerPodTemplateOverride":   p.Spec.Pravega.ControllerPodTemplateOverride,
+		"segmentStorePodTemplateOverride": p.Spec.Pravega.SegmentStorePodTemplateOverride,
+	} {
+		template := corev1.PodTemplateSpec{}
+		if err := util.ApplyPodTemplateOverride(&template, override); err != nil {
+			return fmt.Errorf("invalid %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// validateImageDigests rejects malformed digest pins before they produce
+// unpullable image references
+func validateImageDigests(p *pravegav1alpha1.PravegaCluster) error {
+	var digests []string
+	if p.Spec.Bookkeeper != nil && p.Spec.Bookkeeper.Image != nil {
+		digests = append(digests, p.Spec.Bookkeeper.Image.Digest)
+	}
+	if p.Spec.Pravega != nil {
+		if p.Spec.Pravega.Image != nil {
+			digests = append(digests, p.Spec.Pravega.Image.Digest)
+		}
+		if p.Spec.Pravega.ControllerImage != nil {
+			digests = append(digests, p.Spec.Pravega.ControllerImage.Digest)
+		}
+		if p.Spec.Pravega.SegmentStoreImage != nil {
+			digests = append(digests, p.Spec.Pravega.SegmentStoreImage.Digest)
+		}
+	}
+	for _, digest := range digests {
+		if digest != "" && !imageDigestRegexp.MatchString(digest) {
+			return fmt.Errorf("malformed image digest %q: expected \"sha256:<64 hex chars>\"", digest)
+		}
+	}
+	return nil
+}
+
+func (pwh *pravegaWebhookHandler) validateAuthentication(ctx context.Context, p *pravegav1alpha1.PravegaCluster) error {
+	auth := p.Spec.Authentication
+	if auth == nil {
+		return nil
+	}
+
+	if auth.DelegationTokenTTLSeconds != nil {
+		ttl := *auth.DelegationTokenTTLSeconds
+		if ttl != -1 && ttl <= 0 {
+			return fmt.Errorf("delegation token TTL must be positive, or -1 to disable expiry")
+		}
+		if auth.TokenRefreshThresholdSeconds != nil && ttl > 0 && *auth.TokenRefreshThresholdSeconds >= ttl {
+			return fmt.Errorf("token refresh threshold must be smaller than the delegation token TTL")
+		}
+	}
+	if auth.TokenRefreshThresholdSeconds != nil && *auth.TokenRefreshThresholdSeconds < 0 {
+		return fmt.Errorf("token refresh threshold must not be negative")
+	}
+
+	if auth.Keycloak != nil {
+		if auth.Keycloak.Realm == "" || auth.Keycloak.ClientId == "" || auth.Keycloak.CredentialsSecret == "" {
+			return fmt.Errorf("keycloak authentication requires a realm, a client id and a credentials secret")
+		}
+	}
+
+	if auth.NextTokenSigningKeySecret != "" {
+		if auth.TokenSigningKeySecret == "" {
+			return fmt.Errorf("a signing key rotation requires a current token signing key secret")
+		}
+		if auth.NextTokenSigningKeySecret == auth.TokenSigningKeySecret {
+			return fmt.Errorf("the staged token signing key secret must differ from the current one")
+		}
+	}
+
+	if !auth.IsEnabled() {
+		return nil
+	}
+	if auth.TokenSigningKeySecret == "" {
+		return fmt.Errorf("authentication is enabled but no token signing key secret is configured")
+	}
+	if auth.PasswordAuthSecret == "" && auth.PluginImage == "" && auth.PluginSecret == "" && !auth.IsKeycloakEnabled() {
+		return fmt.Errorf("authentication is enabled but neither a password auth secret nor an auth-handler plugin is configured")
+	}
+	return nil
+}
+
+// validateTLSSecrets rejects TLS configurations whose secrets are missing
+// or incomplete at admission time, instead of letting the pods crash-loop
+// on opaque JVM exceptions. Secrets issued by cert-manager are exempt, they
+// only appear after the Certificates are created
+func (pwh *pravegaWebhookHandler) validateTLSSecrets(ctx context.Context, p *pravegav1alpha1.PravegaCluster) error {
+	tls := p.Spec.TLS
+	if tls == nil || tls.IsCertManagerEnabled() {
+		return nil
+	}
+
+	requiredKeys := map[string][]string{}
+	if tls.Static != nil {
+		for _, name := range []string{
+			tls.Static.ControllerSecret,
+			tls.Static.ControllerGrpcSecret,
+			tls.Static.ControllerRestSecret,
+			tls.Static.SegmentStoreSecret,
+		} {
+			if name != "" {
+				requiredKeys[name] = append(requiredKeys[name], "tls.crt", "tls.key")
+			}
+		}
+	}
+	if tls.CaBundleSecret != "" {
+		requiredKeys[tls.CaBundleSecret] = append(requiredKeys[tls.CaBundleSecret], "ca.crt")
+	}
+	if tls.Bookkeeper != nil && tls.Bookkeeper.Enabled {
+		if tls.Bookkeeper.BookieSecret == "" {
+			return fmt.Errorf("bookkeeper TLS is enabled but no bookie secret is configured")
+		}
+		requiredKeys[tls.Bookkeeper.BookieSecret] = append(requiredKeys[tls.Bookkeeper.BookieSecret],
+			"tls.crt", "tls.key", "ca.crt")
+	}
+	if tls.KeystorePasswordSecret != "" {
+		requiredKeys[tls.KeystorePasswordSecret] = append(requiredKeys[tls.KeystorePasswordSecret], "password")
+	}
+	if tls.TruststorePasswordSecret != "" {
+		requiredKeys[tls.TruststorePasswordSecret] = append(requiredKeys[tls.TruststorePasswordSecret], "password")
+	}
+
+	for name, keys := range requiredKeys {
+		secret := &corev1.Secret{}
+		err := pwh.client.Get(ctx, types.NamespacedName{Name: name, Namespace: p.Namespace}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return fmt.Errorf("TLS secret %s not found", name)
+			}
+			return fmt.Errorf("failed to get TLS secret %s: %v", name, err)
+		}
+		for _, key := range keys {
+			if len(secret.Data[key]) == 0 {
+				return fmt.Errorf("TLS secret %s is missing key %q", name, key)
+			}
+		}
+	}
+	return nil
+}
+
+func (pwh *pravegaWebhookHandler) validateJvmOptions(ctx context.Context, p *pravegav1alpha1.PravegaCluster) error {
+	if p.Spec.Pravega == nil {
+		return nil
+	}
+	for _, opts := range [][]string{p.Spec.Pravega.ControllerJvmOptions, p.Spec.Pravega.SegmentStoreJvmOptions} {
+		seen := map[string]string{}
+		for _, opt := range opts {
+			key := jvmOptionKey(opt)
+			if key == "" {
+				continue
+			}
+			if previous, ok := seen[key]; ok {
+				return fmt.Errorf("duplicate JVM option %q conflicts with %q", opt, previous)
+			}
+			seen[key] = opt
+		}
+	}
+	return nil
+}
+
+// jvmOptionKey reduces a JVM flag to the part identifying the setting, so
+// "-Xmx1g" and "-Xmx2g" are recognized as duplicates. Non -X flags return
+// an empty key and are not checked.
+func jvmOptionKey(opt string) string {
+	if strings.HasPrefix(opt, "-XX:") {
+		key := strings.TrimLeft(strings.TrimPrefix(opt, "-XX:"), "+-")
+		if i := strings.Index(key, "="); i >= 0 {
+			key = key[:i]
+		}
+		return "-XX:" + key
+	}
+	if strings.HasPrefix(opt, "-X") {
+		if i := strings.IndexAny(opt, "0123456789"); i >= 0 {
+			return opt[:i]
+		}
+		return opt
+	}
+	return ""
+}
+
+func (pwh *pravegaWebhookHandler) validateSegmentContainerCount(ctx context.Context, p *pravegav1alpha1.PravegaCluster) error {
+	if p.Spec.Pravega == nil || p.Spec.Pravega.SegmentContainerCount == 0 {
+		return nil
+	}
+
+	count := p.Spec.Pravega.SegmentContainerCount
+	if count < p.Spec.Pravega.SegmentStoreReplicas {
+		return fmt.Errorf("segment container count %d is smaller than the segment store replica count %d", count, p.Spec.Pravega.SegmentStoreReplicas)
+	}
+
+	found := &pravegav1alpha1.PravegaCluster{}
+	nn := types.NamespacedName{
+		Namespace: p.Namespace,
+		Name:      p.Name,
+	}
+	err := pwh.client.Get(context.TODO(), nn, found)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to obtain PravegaCluster resource: %v", err)
+	}
+
+	// The container count determines how segments map to tier-2; changing
+	// it on a bootstrapped cluster corrupts the data
+	if found.Spec.Pravega != nil && found.Spec.Pravega.SegmentContainerCount != 0 &&
+		found.Spec.Pravega.SegmentContainerCount != count {
+		return fmt.Errorf("segment container count cannot be changed after the cluster has bootstrapped")
+	}
+
+	return nil
+}
+
 func (pwh *pravegaWebhookHandler) mutatePravegaVersion(ctx context.Context, p *pravegav1alpha1.PravegaCluster) error {
 	// Identify the request Pravega version
 	// Mutate the version if it is empty
@@ -127,12 +480,43 @@ func (pwh *pravegaWebhookHandler) mutatePravegaVersion(ctx context.Context, p *p
 		return nil
 	}
 
-	// This is an upgrade, check if this requested version is in the upgrade path
+	// This is a version transition, check it against the compatibility matrix
 	normFoundVersion, err := util.NormalizeVersion(foundVersion)
 	if err != nil {
 		// It should never happen
 		return fmt.Errorf("found version is not in valid format, something bad happens: %v", err)
 	}
+
+	if p.Annotations[pravegav1alpha1.SkipVersionCheckAnnotation] == "true" {
+		log.Printf("admitting version transition %s to %s, check skipped by the %s annotation",
+			foundVersion, requestVersion, pravegav1alpha1.SkipVersionCheckAnnotation)
+		return nil
+	}
+
+	// Reverting the spec to the previous or still-running version after a
+	// failed upgrade is how a rollback is triggered; the cluster never
+	// completed the move to the new metadata format, so this transition is
+	// admitted regardless of the matrix
+	if _, errorCondition := found.Status.GetClusterCondition(pravegav1alpha1.ClusterConditionError); errorCondition != nil &&
+		errorCondition.Status == corev1.ConditionTrue && errorCondition.Reason == "UpgradeFailed" &&
+		(requestVersion == found.Status.PreviousVersion || requestVersion == found.Status.CurrentVersion) {
+		return nil
+	}
+
+	downgrade, err := util.CompareVersions(normRequestVersion, normFoundVersion, "<")
+	if err != nil {
+		// It should never happen, both versions normalized above
+		return fmt.Errorf("failed to compare versions %s and %s: %v", foundVersion, requestVersion, err)
+	}
+	if downgrade {
+		// Downgrades are not in the upgrade matrix; they are safe exactly
+		// when both versions share the same metadata format
+		if metadataFormats[normRequestVersion] != metadataFormats[normFoundVersion] {
+			return fmt.Errorf("unsupported downgrade from version %s to %s: the metadata format changed in between", foundVersion, requestVersion)
+		}
+		return nil
+	}
+
 	upgradeList, ok := supportedVersions[normFoundVersion]
 	if !ok {
 		// It should never happen
@@ -18,6 +18,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -260,14 +261,14 @@ var _ = Describe("Admission webhook", func() {
 				})
 			})
 
-			Context("Not in upgrade path", func() {
+			Context("Downgrade across metadata formats", func() {
 				It("should not pass", func() {
 					p.Spec = v1alpha1.ClusterSpec{
 						Version: "0.4.0-001",
 					}
 					err = pwh.mutatePravegaManifest(context.TODO(), p)
 					Ω(err).ShouldNot(BeNil())
-					Ω(err.Error()).To(Equal("unsupported upgrade from version 0.5.0-001 to 0.4.0-001"))
+					Ω(err.Error()).To(Equal("unsupported downgrade from version 0.5.0-001 to 0.4.0-001: the metadata format changed in between"))
 				})
 			})
 		})
@@ -298,4 +299,331 @@ var _ = Describe("Admission webhook", func() {
 
 		})
 	})
+
+	Context("Spec validation", func() {
+		var (
+			p   *v1alpha1.PravegaCluster
+			pwh *pravegaWebhookHandler
+			err error
+		)
+
+		BeforeEach(func() {
+			p = &v1alpha1.PravegaCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      Name,
+					Namespace: Namespace,
+				},
+			}
+			pwh = &pravegaWebhookHandler{client: fake.NewFakeClient()}
+		})
+
+		Context("Pravega options", func() {
+			Context("Options under known namespaces", func() {
+				It("should pass", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						Options: map[string]string{
+							"pravegaservice.containerCount": "4",
+							"metrics.enableStatistics":      "true",
+						},
+					}
+					err = pwh.validateOptions(context.TODO(), p)
+					Ω(err).Should(BeNil())
+				})
+			})
+
+			Context("Option with an unknown namespace", func() {
+				It("should not pass", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						Options: map[string]string{
+							"pravegaservic.containerCount": "4",
+						},
+					}
+					err = pwh.validateOptions(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+					Ω(err.Error()).To(Equal("unknown Pravega option \"pravegaservic.containerCount\": unrecognized property namespace"))
+				})
+			})
+
+			Context("Deprecated option on a version past the rename", func() {
+				It("should not pass", func() {
+					p.Spec.Version = "0.5.0"
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						Options: map[string]string{
+							"autoScale.muteInSeconds": "120",
+						},
+					}
+					err = pwh.validateOptions(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+					Ω(err.Error()).To(Equal("Pravega option \"autoScale.muteInSeconds\" was renamed to \"autoScale.cooldownInSeconds\" in version 0.5.0"))
+				})
+			})
+
+			Context("Deprecated option on a version before the rename", func() {
+				It("should pass", func() {
+					p.Spec.Version = "0.4.0"
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						Options: map[string]string{
+							"autoScale.muteInSeconds": "120",
+						},
+					}
+					err = pwh.validateOptions(context.TODO(), p)
+					Ω(err).Should(BeNil())
+				})
+			})
+		})
+
+		Context("JVM options", func() {
+			Context("Distinct options", func() {
+				It("should pass", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						SegmentStoreJvmOptions: []string{"-Xmx2g", "-XX:MaxDirectMemorySize=2g", "-XX:+UseG1GC"},
+					}
+					err = pwh.validateJvmOptions(context.TODO(), p)
+					Ω(err).Should(BeNil())
+				})
+			})
+
+			Context("The same -X flag with different sizes", func() {
+				It("should not pass", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						SegmentStoreJvmOptions: []string{"-Xmx2g", "-Xmx4g"},
+					}
+					err = pwh.validateJvmOptions(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+					Ω(err.Error()).To(Equal("duplicate JVM option \"-Xmx4g\" conflicts with \"-Xmx2g\""))
+				})
+			})
+
+			Context("The same -XX switch enabled and disabled", func() {
+				It("should not pass", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						ControllerJvmOptions: []string{"-XX:+UseG1GC", "-XX:-UseG1GC"},
+					}
+					err = pwh.validateJvmOptions(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+				})
+			})
+
+			Context("The same -XX setting with different values", func() {
+				It("should not pass", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						ControllerJvmOptions: []string{"-XX:MaxDirectMemorySize=1g", "-XX:MaxDirectMemorySize=2g"},
+					}
+					err = pwh.validateJvmOptions(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+				})
+			})
+
+			Context("Repeated -D properties", func() {
+				It("should pass, only -X flags are checked", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						ControllerJvmOptions: []string{"-Dname=a", "-Dname=b"},
+					}
+					err = pwh.validateJvmOptions(context.TODO(), p)
+					Ω(err).Should(BeNil())
+				})
+			})
+
+			Context("The same flag on different components", func() {
+				It("should pass", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						ControllerJvmOptions:   []string{"-Xmx2g"},
+						SegmentStoreJvmOptions: []string{"-Xmx2g"},
+					}
+					err = pwh.validateJvmOptions(context.TODO(), p)
+					Ω(err).Should(BeNil())
+				})
+			})
+		})
+
+		Context("Authentication", func() {
+			Context("Password auth with a signing key", func() {
+				It("should pass", func() {
+					p.Spec.Authentication = &v1alpha1.AuthenticationSpec{
+						Enabled:               true,
+						TokenSigningKeySecret: "signing-key",
+						PasswordAuthSecret:    "passwd",
+					}
+					err = pwh.validateAuthentication(context.TODO(), p)
+					Ω(err).Should(BeNil())
+				})
+			})
+
+			Context("Enabled without a signing key", func() {
+				It("should not pass", func() {
+					p.Spec.Authentication = &v1alpha1.AuthenticationSpec{
+						Enabled:            true,
+						PasswordAuthSecret: "passwd",
+					}
+					err = pwh.validateAuthentication(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+				})
+			})
+
+			Context("Enabled without a user database", func() {
+				It("should not pass", func() {
+					p.Spec.Authentication = &v1alpha1.AuthenticationSpec{
+						Enabled:               true,
+						TokenSigningKeySecret: "signing-key",
+					}
+					err = pwh.validateAuthentication(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+				})
+			})
+
+			Context("Keycloak without a client id", func() {
+				It("should not pass", func() {
+					p.Spec.Authentication = &v1alpha1.AuthenticationSpec{
+						Keycloak: &v1alpha1.KeycloakSpec{
+							Realm:             "pravega",
+							CredentialsSecret: "keycloak-credentials",
+						},
+					}
+					err = pwh.validateAuthentication(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+				})
+			})
+
+			Context("Refresh threshold not below the token TTL", func() {
+				It("should not pass", func() {
+					ttl := int32(600)
+					threshold := int32(600)
+					p.Spec.Authentication = &v1alpha1.AuthenticationSpec{
+						DelegationTokenTTLSeconds:    &ttl,
+						TokenRefreshThresholdSeconds: &threshold,
+					}
+					err = pwh.validateAuthentication(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+				})
+			})
+
+			Context("Staged rotation key equal to the current one", func() {
+				It("should not pass", func() {
+					p.Spec.Authentication = &v1alpha1.AuthenticationSpec{
+						TokenSigningKeySecret:     "signing-key",
+						NextTokenSigningKeySecret: "signing-key",
+					}
+					err = pwh.validateAuthentication(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+				})
+			})
+		})
+
+		Context("Image digests", func() {
+			Context("Well-formed digest", func() {
+				It("should pass", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						Image: &v1alpha1.PravegaImageSpec{
+							ImageSpec: v1alpha1.ImageSpec{
+								Repository: "pravega/pravega",
+								Digest:     "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+							},
+						},
+					}
+					err = validateImageDigests(p)
+					Ω(err).Should(BeNil())
+				})
+			})
+
+			Context("Digest without the algorithm prefix", func() {
+				It("should not pass", func() {
+					p.Spec.Pravega = &v1alpha1.PravegaSpec{
+						Image: &v1alpha1.PravegaImageSpec{
+							ImageSpec: v1alpha1.ImageSpec{
+								Repository: "pravega/pravega",
+								Digest:     "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+							},
+						},
+					}
+					err = validateImageDigests(p)
+					Ω(err).ShouldNot(BeNil())
+				})
+			})
+
+			Context("Truncated digest", func() {
+				It("should not pass", func() {
+					p.Spec.Bookkeeper = &v1alpha1.BookkeeperSpec{
+						Image: &v1alpha1.BookkeeperImageSpec{
+							ImageSpec: v1alpha1.ImageSpec{
+								Repository: "pravega/bookkeeper",
+								Digest:     "sha256:9f86d081884c7d",
+							},
+						},
+					}
+					err = validateImageDigests(p)
+					Ω(err).ShouldNot(BeNil())
+				})
+			})
+		})
+
+		Context("TLS secrets", func() {
+			var secret *corev1.Secret
+
+			BeforeEach(func() {
+				secret = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "controller-tls",
+						Namespace: Namespace,
+					},
+					Data: map[string][]byte{
+						"tls.crt": []byte("cert"),
+						"tls.key": []byte("key"),
+					},
+				}
+				p.Spec.TLS = &v1alpha1.TLSPolicy{
+					Static: &v1alpha1.StaticTLS{
+						ControllerSecret: "controller-tls",
+					},
+				}
+			})
+
+			Context("Complete static secret", func() {
+				It("should pass", func() {
+					pwh = &pravegaWebhookHandler{client: fake.NewFakeClient(secret)}
+					err = pwh.validateTLSSecrets(context.TODO(), p)
+					Ω(err).Should(BeNil())
+				})
+			})
+
+			Context("Secret does not exist", func() {
+				It("should not pass", func() {
+					err = pwh.validateTLSSecrets(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+					Ω(err.Error()).To(Equal("TLS secret controller-tls not found"))
+				})
+			})
+
+			Context("Secret missing the private key", func() {
+				It("should not pass", func() {
+					delete(secret.Data, "tls.key")
+					pwh = &pravegaWebhookHandler{client: fake.NewFakeClient(secret)}
+					err = pwh.validateTLSSecrets(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+					Ω(err.Error()).To(Equal("TLS secret controller-tls is missing key \"tls.key\""))
+				})
+			})
+
+			Context("CA bundle secret without ca.crt", func() {
+				It("should not pass", func() {
+					p.Spec.TLS = &v1alpha1.TLSPolicy{CaBundleSecret: "controller-tls"}
+					pwh = &pravegaWebhookHandler{client: fake.NewFakeClient(secret)}
+					err = pwh.validateTLSSecrets(context.TODO(), p)
+					Ω(err).ShouldNot(BeNil())
+					Ω(err.Error()).To(Equal("TLS secret controller-tls is missing key \"ca.crt\""))
+				})
+			})
+
+			Context("Secrets issued by cert-manager", func() {
+				It("should pass without the secrets existing yet", func() {
+					p.Spec.TLS = &v1alpha1.TLSPolicy{
+						CertManager: &v1alpha1.CertManagerTLS{
+							IssuerRef: v1alpha1.IssuerRef{Name: "selfsigned"},
+						},
+					}
+					err = pwh.validateTLSSecrets(context.TODO(), p)
+					Ω(err).Should(BeNil())
+				})
+			})
+		})
+	})
 })